@@ -4,6 +4,7 @@
 package main
 
 import (
+	"errors"
 	"math/rand"
 	"os"
 	"time"
@@ -21,7 +22,8 @@ import (
 func main() {
 	err := nonExitingMain()
 	if err != nil {
-		if typedErr, ok := err.(cmdapp.ExitStatus); ok {
+		var typedErr cmdapp.ExitStatus
+		if errors.As(err, &typedErr) {
 			os.Exit(typedErr.ExitStatus())
 		}
 		os.Exit(1)
@@ -19,6 +19,10 @@ type Meta struct {
 
 	UsedGVs []schema.GroupVersion `json:"usedGVs,omitempty"`
 	UsedGKs *[]schema.GroupKind   `json:"usedGKs,omitempty"`
+
+	// DependsOn lists apps within the same app group
+	// that are deployed before (and deleted after) this app
+	DependsOn []string `json:"dependsOn,omitempty"`
 }
 
 func NewAppMetaFromData(data map[string]string) (Meta, error) {
@@ -9,11 +9,13 @@ import (
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 )
 
 type ChangeImpl struct {
 	name   string
+	uid    types.UID
 	nsName string
 
 	coreClient kubernetes.Interface
@@ -29,12 +31,15 @@ var _ Change = &ChangeImpl{}
 func (c *ChangeImpl) Name() string     { return c.name }
 func (c *ChangeImpl) Meta() ChangeMeta { return c.meta }
 
-func (c *ChangeImpl) Fail() error {
+func (c *ChangeImpl) Fail() error { return c.FailWithReason("") }
+
+func (c *ChangeImpl) FailWithReason(reason string) error {
 	return c.update(func(meta *ChangeMeta) {
 		falseBool := false
 
 		meta.Successful = &falseBool
 		meta.FinishedAt = time.Now().UTC()
+		meta.FailureReason = reason
 	})
 }
 
@@ -47,8 +52,24 @@ func (c *ChangeImpl) Succeed() error {
 	})
 }
 
+func (c *ChangeImpl) RecordResources(recorded RecordedResources) error {
+	if c.appChangesMaxToKeep == 0 {
+		return nil
+	}
+	return NewChangeSnapshot(c.name, c.nsName, c.coreClient).Create(c.name, c.uid, recorded)
+}
+
+func (c *ChangeImpl) Resources() (RecordedResources, bool, error) {
+	return NewChangeSnapshot(c.name, c.nsName, c.coreClient).Resources()
+}
+
 func (c *ChangeImpl) Delete() error {
-	err := c.coreClient.CoreV1().ConfigMaps(c.nsName).Delete(context.TODO(), c.name, metav1.DeleteOptions{})
+	err := NewChangeSnapshot(c.name, c.nsName, c.coreClient).Delete()
+	if err != nil {
+		return err
+	}
+
+	err = c.coreClient.CoreV1().ConfigMaps(c.nsName).Delete(context.TODO(), c.name, metav1.DeleteOptions{})
 	if err != nil {
 		return fmt.Errorf("Deleting app change: %w", err)
 	}
@@ -85,8 +106,13 @@ type NoopChange struct{}
 
 var _ Change = NoopChange{}
 
-func (NoopChange) Name() string     { return "" }
-func (NoopChange) Meta() ChangeMeta { return ChangeMeta{} }
-func (NoopChange) Fail() error      { return nil }
-func (NoopChange) Succeed() error   { return nil }
-func (NoopChange) Delete() error    { return nil }
+func (NoopChange) Name() string                            { return "" }
+func (NoopChange) Meta() ChangeMeta                        { return ChangeMeta{} }
+func (NoopChange) Fail() error                             { return nil }
+func (NoopChange) FailWithReason(string) error             { return nil }
+func (NoopChange) Succeed() error                          { return nil }
+func (NoopChange) Delete() error                           { return nil }
+func (NoopChange) RecordResources(RecordedResources) error { return nil }
+func (NoopChange) Resources() (RecordedResources, bool, error) {
+	return RecordedResources{}, false, nil
+}
@@ -13,8 +13,9 @@ type ChangeMeta struct {
 	StartedAt  time.Time `json:"startedAt"`
 	FinishedAt time.Time `json:"finishedAt,omitempty"`
 
-	Successful  *bool  `json:"successful,omitempty"`
-	Description string `json:"description,omitempty"`
+	Successful    *bool  `json:"successful,omitempty"`
+	Description   string `json:"description,omitempty"`
+	FailureReason string `json:"failureReason,omitempty"`
 
 	Namespaces []string `json:"namespaces,omitempty"`
 }
@@ -0,0 +1,207 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	isChangeSnapshotLabelKey   = "kapp.k14s.io/is-app-change-snapshot"
+	isChangeSnapshotLabelValue = ""
+
+	changeSnapshotSecretType = "kapp.k14s.io/app-change-snapshot"
+	changeSnapshotDataKey    = "resources.json.gz"
+	changeSnapshotOptsKey    = "opts.json"
+
+	// Leave room for object metadata within Secret size limit (1MiB)
+	changeSnapshotMaxSize = 1000 * 1024
+)
+
+// RecordedResources are resources provided to an app change
+// together with options that affected how they were prepared,
+// so that the same set of resources can be calculated again.
+type RecordedResources struct {
+	Resources []ctlres.Resource
+	Opts      RecordedResourcesOpts
+}
+
+type RecordedResourcesOpts struct {
+	IntoNamespace string   `json:"intoNamespace,omitempty"`
+	MapNamespaces []string `json:"mapNamespaces,omitempty"`
+
+	ResourceFilter *ctlres.ResourceFilter `json:"resourceFilter,omitempty"`
+	// BoolFilter is not serialized as part of ResourceFilter
+	BoolFilter *ctlres.BoolFilter `json:"boolFilter,omitempty"`
+}
+
+// ChangeSnapshot stores resources applied as part of an app change
+// in a Secret (resources may contain sensitive data) named after the change.
+// Secret is owned by app change's ConfigMap so that cluster garbage collector
+// removes it even if app change is deleted by other means.
+type ChangeSnapshot struct {
+	name   string
+	nsName string
+
+	coreClient kubernetes.Interface
+}
+
+func NewChangeSnapshot(name, nsName string, coreClient kubernetes.Interface) ChangeSnapshot {
+	return ChangeSnapshot{name, nsName, coreClient}
+}
+
+func (s ChangeSnapshot) Create(changeName string, changeUID types.UID, recorded RecordedResources) error {
+	data, err := s.encode(recorded.Resources)
+	if err != nil {
+		return fmt.Errorf("Encoding app change snapshot: %w", err)
+	}
+
+	if len(data) > changeSnapshotMaxSize {
+		return fmt.Errorf("Skipped recording resources for app change '%s' "+
+			"since they exceed maximum size (%d KiB > %d KiB)", changeName, len(data)/1024, changeSnapshotMaxSize/1024)
+	}
+
+	optsBytes, err := json.Marshal(recorded.Opts)
+	if err != nil {
+		return fmt.Errorf("Encoding app change snapshot options: %w", err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      s.name,
+			Namespace: s.nsName,
+			Labels: map[string]string{
+				isChangeSnapshotLabelKey: isChangeSnapshotLabelValue,
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Name:       changeName,
+				UID:        changeUID,
+			}},
+		},
+		Type: changeSnapshotSecretType,
+		Data: map[string][]byte{
+			changeSnapshotDataKey: data,
+			changeSnapshotOptsKey: optsBytes,
+		},
+	}
+
+	_, err = s.coreClient.CoreV1().Secrets(s.nsName).Create(context.TODO(), secret, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("Recording resources for app change '%s': %w", changeName, err)
+	}
+
+	return nil
+}
+
+// Resources returns false if app change does not have recorded resources
+// (e.g. it was created by an older kapp version or snapshot was too large).
+func (s ChangeSnapshot) Resources() (RecordedResources, bool, error) {
+	secret, err := s.coreClient.CoreV1().Secrets(s.nsName).Get(context.TODO(), s.name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return RecordedResources{}, false, nil
+		}
+		return RecordedResources{}, false, fmt.Errorf("Getting app change snapshot: %w", err)
+	}
+
+	data, found := secret.Data[changeSnapshotDataKey]
+	if !found || secret.Type != changeSnapshotSecretType {
+		return RecordedResources{}, false, nil
+	}
+
+	resources, err := s.decode(data)
+	if err != nil {
+		return RecordedResources{}, false, fmt.Errorf("Decoding app change snapshot: %w", err)
+	}
+
+	recorded := RecordedResources{Resources: resources}
+
+	// Snapshots created by older kapp versions do not have options
+	if optsBytes, found := secret.Data[changeSnapshotOptsKey]; found {
+		err = json.Unmarshal(optsBytes, &recorded.Opts)
+		if err != nil {
+			return RecordedResources{}, false, fmt.Errorf("Decoding app change snapshot options: %w", err)
+		}
+	}
+
+	return recorded, true, nil
+}
+
+// Delete tolerates missing permissions on Secrets since snapshot
+// is garbage collected together with its app change anyway.
+func (s ChangeSnapshot) Delete() error {
+	err := s.coreClient.CoreV1().Secrets(s.nsName).Delete(context.TODO(), s.name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) && !errors.IsForbidden(err) {
+		return fmt.Errorf("Deleting app change snapshot: %w", err)
+	}
+
+	return nil
+}
+
+func (s ChangeSnapshot) encode(resources []ctlres.Resource) ([]byte, error) {
+	list := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"items":      []interface{}{},
+	}
+
+	var items []interface{}
+	for _, res := range resources {
+		items = append(items, res.DeepCopyRaw())
+	}
+	if len(items) > 0 {
+		list["items"] = items
+	}
+
+	listBytes, err := json.Marshal(list)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	gzipWriter := gzip.NewWriter(&buf)
+
+	_, err = gzipWriter.Write(listBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	err = gzipWriter.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (s ChangeSnapshot) decode(data []byte) ([]ctlres.Resource, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	defer gzipReader.Close()
+
+	listBytes, err := io.ReadAll(gzipReader)
+	if err != nil {
+		return nil, err
+	}
+
+	return ctlres.NewResourcesFromBytes(listBytes)
+}
@@ -21,11 +21,14 @@ type App interface {
 	UsedGVs() ([]schema.GroupVersion, error)
 	UsedGKs() (*[]schema.GroupKind, error)
 	UpdateUsedGVsAndGKs([]schema.GroupVersion, []schema.GroupKind) error
+	UpdateDependsOn([]string) error
 
 	CreateOrUpdate(string, map[string]string, bool) (bool, error)
 	Exists() (bool, string, error)
 	Delete() error
 	Rename(string, string) error
+	// Lock prevents concurrent changes to the app until returned lock is released
+	Lock(LockOpts) (AppLock, error)
 
 	// Sorted as first is oldest
 	Changes() ([]Change, error)
@@ -34,11 +37,26 @@ type App interface {
 	GCChanges(max int, reviewFunc func(changesToDelete []Change) error) (int, int, error)
 }
 
+type LockOpts struct {
+	// Description identifies lock holder to other kapp runs (e.g. "kapp deploy")
+	Description string
+	// Force takes over lock even if it's held by another kapp run
+	Force bool
+}
+
+type AppLock interface {
+	Unlock() error
+}
+
 type Change interface {
 	Name() string
 	Meta() ChangeMeta
+	RecordResources(RecordedResources) error
+	// Resources returns resources applied as part of this change if they were recorded
+	Resources() (RecordedResources, bool, error)
 
 	Fail() error
+	FailWithReason(string) error
 	Succeed() error
 
 	Delete() error
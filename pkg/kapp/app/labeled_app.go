@@ -43,6 +43,7 @@ func (a *LabeledApp) LabelSelector() (labels.Selector, error) {
 func (a *LabeledApp) UsedGVs() ([]schema.GroupVersion, error)                             { return nil, nil }
 func (a *LabeledApp) UsedGKs() (*[]schema.GroupKind, error)                               { return nil, nil }
 func (a *LabeledApp) UpdateUsedGVsAndGKs([]schema.GroupVersion, []schema.GroupKind) error { return nil }
+func (a *LabeledApp) UpdateDependsOn([]string) error                                      { return nil }
 
 func (a *LabeledApp) CreateOrUpdate(_ string, _ map[string]string, _ bool) (bool, error) {
 	return false, nil
@@ -73,6 +74,13 @@ func (a *LabeledApp) Delete() error {
 
 func (a *LabeledApp) Rename(_ string, _ string) error { return fmt.Errorf("Not supported") }
 
+// Lock is a noop since labeled apps do not have a place to record lock
+func (a *LabeledApp) Lock(LockOpts) (AppLock, error) { return noopAppLock{}, nil }
+
+type noopAppLock struct{}
+
+func (noopAppLock) Unlock() error { return nil }
+
 func (a *LabeledApp) Meta() (Meta, error) { return Meta{}, nil }
 
 func (a *LabeledApp) Changes() ([]Change, error)                  { return nil, nil }
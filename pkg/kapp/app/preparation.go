@@ -32,6 +32,8 @@ type PrepareResourcesOpts struct {
 	IntoNamespace    string   // this ns is allowed automatically
 	MapNamespaces    []string // this ns is allowed automatically
 	DefaultNamespace string   // this ns is allowed automatically
+
+	Nonce string // defaults to current time
 }
 
 func NewPreparation(resourceTypes ctlres.ResourceTypes, opts PrepareResourcesOpts) Preparation {
@@ -111,11 +113,16 @@ func (a Preparation) placeIntoNamespace(resources []ctlres.Resource) ([]ctlres.R
 }
 
 func (a Preparation) addNonce(resources []ctlres.Resource) ([]ctlres.Resource, error) {
+	nonce := a.opts.Nonce
+	if len(nonce) == 0 {
+		nonce = fmt.Sprintf("%d", time.Now().UTC().UnixNano())
+	}
+
 	addNonceMod := ctlres.StringMapAppendMod{
 		ResourceMatcher: ctlres.AllMatcher{},
 		Path:            ctlres.NewPathFromStrings([]string{"metadata", "annotations"}),
 		KVs: map[string]string{
-			nonceAnnKey: fmt.Sprintf("%d", time.Now().UTC().UnixNano()),
+			nonceAnnKey: nonce,
 		},
 	}
 
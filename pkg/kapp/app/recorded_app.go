@@ -13,6 +13,7 @@ import (
 
 	"carvel.dev/kapp/pkg/kapp/logger"
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"carvel.dev/kapp/pkg/kapp/util"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -130,6 +131,12 @@ func (a *RecordedApp) UpdateUsedGVsAndGKs(gvs []schema.GroupVersion, gks []schem
 	})
 }
 
+func (a *RecordedApp) UpdateDependsOn(names []string) error {
+	return a.update(func(meta *Meta) {
+		meta.DependsOn = names
+	})
+}
+
 func (a *RecordedApp) CreateOrUpdate(prevAppName string, labels map[string]string, isDiffRun bool) (bool, error) {
 	defer a.logger.DebugFunc("CreateOrUpdate").Finish()
 
@@ -548,12 +555,14 @@ func (a *RecordedApp) BeginChange(meta ChangeMeta, appChangesMaxToKeep int) (Cha
 }
 
 func (a *RecordedApp) update(doFunc func(*Meta)) error {
-	name := a.name
-	if a.isMigrated {
-		name = a.fqName()
-	}
+	// App may be updated concurrently (e.g. when its lock is renewed)
+	return util.Retry2(100*time.Millisecond, 5*time.Second, errors.IsConflict, func() error {
+		return a.updateOnce(doFunc)
+	})
+}
 
-	change, err := a.coreClient.CoreV1().ConfigMaps(a.nsName).Get(context.TODO(), name, metav1.GetOptions{})
+func (a *RecordedApp) updateOnce(doFunc func(*Meta)) error {
+	change, err := a.coreClient.CoreV1().ConfigMaps(a.nsName).Get(context.TODO(), a.configMapName(), metav1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("Getting app: %w", err)
 	}
@@ -580,6 +589,13 @@ func (a *RecordedApp) update(doFunc func(*Meta)) error {
 	return nil
 }
 
+func (a *RecordedApp) configMapName() string {
+	if a.isMigrated {
+		return a.fqName()
+	}
+	return a.name
+}
+
 type appTrackingChange struct {
 	change *ChangeImpl
 	app    *RecordedApp
@@ -590,8 +606,10 @@ var _ Change = appTrackingChange{}
 func (c appTrackingChange) Name() string     { return c.change.Name() }
 func (c appTrackingChange) Meta() ChangeMeta { return c.change.meta }
 
-func (c appTrackingChange) Fail() error {
-	err := c.change.Fail()
+func (c appTrackingChange) Fail() error { return c.FailWithReason("") }
+
+func (c appTrackingChange) FailWithReason(reason string) error {
+	err := c.change.FailWithReason(reason)
 	if err != nil {
 		return err
 	}
@@ -612,6 +630,14 @@ func (c appTrackingChange) Succeed() error {
 	return err
 }
 
+func (c appTrackingChange) RecordResources(recorded RecordedResources) error {
+	return c.change.RecordResources(recorded)
+}
+
+func (c appTrackingChange) Resources() (RecordedResources, bool, error) {
+	return c.change.Resources()
+}
+
 func (c appTrackingChange) Delete() error {
 	return c.change.Delete()
 }
@@ -12,6 +12,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
 )
@@ -103,7 +104,12 @@ func (a RecordedAppChanges) DeleteAll() error {
 	}
 
 	for _, change := range changes.Items {
-		err := a.coreClient.CoreV1().ConfigMaps(a.nsName).Delete(context.TODO(), change.Name, metav1.DeleteOptions{})
+		err := NewChangeSnapshot(change.Name, a.nsName, a.coreClient).Delete()
+		if err != nil {
+			return err
+		}
+
+		err = a.coreClient.CoreV1().ConfigMaps(a.nsName).Delete(context.TODO(), change.Name, metav1.DeleteOptions{})
 		if err != nil {
 			return err
 		}
@@ -138,6 +144,7 @@ func (a RecordedAppChanges) Begin(meta ChangeMeta, appChangesMaxToKeep int) (*Ch
 	}
 
 	changeName := ""
+	var changeUID types.UID
 
 	if appChangesMaxToKeep > 0 {
 		createdChange, err := a.coreClient.CoreV1().ConfigMaps(a.nsName).Create(context.TODO(), configMap, metav1.CreateOptions{})
@@ -145,10 +152,12 @@ func (a RecordedAppChanges) Begin(meta ChangeMeta, appChangesMaxToKeep int) (*Ch
 			return nil, fmt.Errorf("Creating app change: %w", err)
 		}
 		changeName = createdChange.Name
+		changeUID = createdChange.UID
 	}
 
 	change := &ChangeImpl{
 		name:                changeName,
+		uid:                 changeUID,
 		nsName:              a.nsName,
 		coreClient:          a.coreClient,
 		meta:                newMeta,
@@ -0,0 +1,191 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"carvel.dev/kapp/pkg/kapp/util"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	appLockAnnKey = "kapp.k14s.io/lock"
+
+	// Lock is considered stale (e.g. holder was killed)
+	// if it was not renewed within this duration
+	appLockDuration      = 2 * time.Minute
+	appLockRenewInterval = 30 * time.Second
+)
+
+type appLockRecord struct {
+	Holder     string    `json:"holder"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+	RenewedAt  time.Time `json:"renewedAt"`
+}
+
+func (r appLockRecord) IsStale(now time.Time) bool {
+	return now.Sub(r.RenewedAt) > appLockDuration
+}
+
+// Lock marks app as being changed so that concurrent kapp runs
+// against the same app fail instead of racing each other.
+// Lock is periodically renewed until it's released.
+func (a *RecordedApp) Lock(opts LockOpts) (AppLock, error) {
+	defer a.logger.DebugFunc("Lock").Finish()
+
+	holder, err := newAppLockHolder(opts.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.updateLock(func(existing *appLockRecord) (*appLockRecord, error) {
+		now := time.Now().UTC()
+		if existing != nil && existing.Holder != holder && !existing.IsStale(now) && !opts.Force {
+			return nil, fmt.Errorf("App '%s' (namespace: %s) is locked by '%s' since %s "+
+				"(hint: wait for it to finish or use --force-unlock if it's no longer running)",
+				a.name, a.nsName, existing.Holder, existing.AcquiredAt.Format(time.RFC3339))
+		}
+		if existing != nil && existing.Holder != holder {
+			a.logger.Info("Taking over lock held by '%s'", existing.Holder)
+		}
+		return &appLockRecord{Holder: holder, AcquiredAt: now, RenewedAt: now}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &recordedAppLock{app: a, holder: holder, stopCh: make(chan struct{})}
+	go lock.renew()
+
+	return lock, nil
+}
+
+// updateLock updates lock record on app's ConfigMap; returned nil record removes it
+func (a *RecordedApp) updateLock(doFunc func(*appLockRecord) (*appLockRecord, error)) error {
+	return util.Retry2(100*time.Millisecond, 5*time.Second, errors.IsConflict, func() error {
+		app, err := a.coreClient.CoreV1().ConfigMaps(a.nsName).Get(context.TODO(), a.configMapName(), metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("Getting app: %w", err)
+		}
+
+		var existing *appLockRecord
+
+		if val, found := app.Annotations[appLockAnnKey]; found {
+			var record appLockRecord
+			// Treat unparseable lock as non-existent
+			if json.Unmarshal([]byte(val), &record) == nil {
+				existing = &record
+			}
+		}
+
+		record, err := doFunc(existing)
+		if err != nil {
+			return err
+		}
+
+		if record == nil {
+			if existing == nil {
+				return nil
+			}
+			delete(app.Annotations, appLockAnnKey)
+		} else {
+			bs, err := json.Marshal(record)
+			if err != nil {
+				return fmt.Errorf("Marshaling app lock: %w", err)
+			}
+			if app.Annotations == nil {
+				app.Annotations = map[string]string{}
+			}
+			app.Annotations[appLockAnnKey] = string(bs)
+		}
+
+		_, err = a.coreClient.CoreV1().ConfigMaps(a.nsName).Update(context.TODO(), app, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("Updating app lock: %w", err)
+		}
+
+		return nil
+	})
+}
+
+type recordedAppLock struct {
+	app    *RecordedApp
+	holder string
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+var _ AppLock = &recordedAppLock{}
+
+func (l *recordedAppLock) Unlock() error {
+	l.stopOnce.Do(func() { close(l.stopCh) })
+
+	err := l.app.updateLock(func(existing *appLockRecord) (*appLockRecord, error) {
+		if existing != nil && existing.Holder != l.holder {
+			// Lock was forcefully taken over, hence leave it as is
+			return existing, nil
+		}
+		return nil, nil
+	})
+	if err != nil && errors.IsNotFound(err) {
+		// App was deleted together with its lock
+		return nil
+	}
+	return err
+}
+
+func (l *recordedAppLock) renew() {
+	for {
+		select {
+		case <-time.After(appLockRenewInterval):
+		case <-l.stopCh:
+			return
+		}
+
+		var lost bool
+
+		err := l.app.updateLock(func(existing *appLockRecord) (*appLockRecord, error) {
+			if existing == nil || existing.Holder != l.holder {
+				lost = true
+				return existing, nil
+			}
+			existing.RenewedAt = time.Now().UTC()
+			return existing, nil
+		})
+		if err != nil {
+			l.app.logger.Info("Failed to renew app lock: %s", err)
+			continue
+		}
+		if lost {
+			l.app.logger.Info("App lock was taken over by another holder")
+			return
+		}
+	}
+}
+
+func newAppLockHolder(desc string) (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	randBs := make([]byte, 4)
+
+	_, err = rand.Read(randBs)
+	if err != nil {
+		return "", fmt.Errorf("Generating app lock holder: %w", err)
+	}
+
+	return fmt.Sprintf("%s (host: %s, pid: %d, id: %s)", desc, hostname, os.Getpid(), hex.EncodeToString(randBs)), nil
+}
@@ -3,12 +3,21 @@
 
 package app
 
+import (
+	"errors"
+)
+
 type Touch struct {
 	App              App
 	Description      string
 	Namespaces       []string
+	Resources        *RecordedResources
 	IgnoreSuccessErr bool
 
+	// WarningFunc is called when resources could not be recorded
+	// (app change proceeds since recorded resources are only used for rollbacks)
+	WarningFunc func(error)
+
 	AppChangesMaxToKeep int
 }
 
@@ -23,9 +32,21 @@ func (t Touch) Do(doFunc func() error) error {
 		return err
 	}
 
+	if t.Resources != nil {
+		err := change.RecordResources(*t.Resources)
+		if err != nil && t.WarningFunc != nil {
+			t.WarningFunc(err)
+		}
+	}
+
 	workErr := doFunc()
 	if workErr != nil {
-		_ = change.Fail()
+		var failedErr ChangeFailedError
+		if errors.As(workErr, &failedErr) {
+			_ = change.FailWithReason(failedErr.Reason)
+		} else {
+			_ = change.Fail()
+		}
 		return workErr
 	}
 
@@ -38,3 +59,12 @@ func (t Touch) Do(doFunc func() error) error {
 
 	return nil
 }
+
+// ChangeFailedError allows to record a reason why app change failed
+type ChangeFailedError struct {
+	Err    error
+	Reason string
+}
+
+func (e ChangeFailedError) Error() string { return e.Err.Error() }
+func (e ChangeFailedError) Unwrap() error { return e.Err }
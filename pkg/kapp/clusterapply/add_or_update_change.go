@@ -5,6 +5,7 @@ package clusterapply
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	ctlconf "carvel.dev/kapp/pkg/kapp/config"
@@ -12,6 +13,8 @@ import (
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	"carvel.dev/kapp/pkg/kapp/util"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 const (
@@ -25,10 +28,15 @@ const (
 	updateStrategyFallbackOnReplaceAnnValue ClusterChangeApplyStrategyOp = "fallback-on-replace"
 	updateStrategyAlwaysReplaceAnnValue     ClusterChangeApplyStrategyOp = "always-replace"
 	updateStrategySkipAnnValue              ClusterChangeApplyStrategyOp = "skip"
+	updateStrategyServerSideApplyAnnValue   ClusterChangeApplyStrategyOp = "server-side-apply"
+	updateStrategyThreeWayMergeAnnValue     ClusterChangeApplyStrategyOp = "three-way-merge-patch"
 )
 
 type AddOrUpdateChangeOpts struct {
 	DefaultUpdateStrategy string
+
+	ServerSideApplyFieldManager   string
+	ServerSideApplyForceConflicts bool
 }
 
 type AddOrUpdateChange struct {
@@ -69,6 +77,11 @@ func (c AddOrUpdateChange) ApplyStrategy() (ApplyStrategy, error) {
 	case ctldiff.ChangeOpUpdate:
 		newRes := c.change.NewResource()
 
+		// Hooks are recreated so that they run again
+		if _, isHook := newRes.Annotations()[ctlres.HookAnnKey]; isHook {
+			return UpdateAlwaysReplaceStrategy{c}, nil
+		}
+
 		strategy, found := newRes.Annotations()[updateStrategyAnnKey]
 		if !found {
 			strategy = c.opts.DefaultUpdateStrategy
@@ -87,6 +100,12 @@ func (c AddOrUpdateChange) ApplyStrategy() (ApplyStrategy, error) {
 		case updateStrategySkipAnnValue:
 			return UpdateSkipStrategy{c}, nil
 
+		case updateStrategyServerSideApplyAnnValue:
+			return UpdateServerSideApplyStrategy{c}, nil
+
+		case updateStrategyThreeWayMergeAnnValue:
+			return UpdateThreeWayMergeStrategy{c}, nil
+
 		default:
 			return nil, fmt.Errorf("Unknown update strategy: %s", strategy)
 		}
@@ -148,7 +167,7 @@ func (c AddOrUpdateChange) tryToResolveUpdateConflict(
 		if recalcChanges[0].Op() != ctldiff.ChangeOpUpdate {
 			return fmt.Errorf("Expected recalculated change to be an update")
 		}
-		if recalcChanges[0].OpsDiff().MinimalMD5() != c.change.OpsDiff().MinimalMD5() {
+		if recalcChanges[0].OpsDiff().MinimalMD5() != c.approvedOpsDiff().MinimalMD5() {
 			errMsg := fmt.Sprintf(errMsgPrefix+"(approved diff no longer matches): %s", origErr)
 
 			textDiff, err := recalcChanges[0].ConfigurableTextDiff().Masked(c.diffMaskRules)
@@ -172,6 +191,15 @@ func (c AddOrUpdateChange) tryToResolveUpdateConflict(
 	return fmt.Errorf(errMsgPrefix+"(tried multiple times): %w", origErr)
 }
 
+// approvedOpsDiff returns diff of original change since recalculated
+// changes are not dry run against the server
+func (c AddOrUpdateChange) approvedOpsDiff() ctldiff.OpsDiff {
+	if dryRunChange, ok := c.change.(DryRunChange); ok {
+		return dryRunChange.Change.OpsDiff()
+	}
+	return c.change.OpsDiff()
+}
+
 func (c AddOrUpdateChange) tryToUpdateAfterCreateConflict(allowNoopUpdates bool) error {
 	var lastUpdateErr error
 
@@ -398,3 +426,94 @@ func (c UpdateSkipStrategy) Op() ClusterChangeApplyStrategyOp {
 }
 
 func (c UpdateSkipStrategy) Apply() error { return nil }
+
+type UpdateServerSideApplyStrategy struct {
+	aou AddOrUpdateChange
+}
+
+func (c UpdateServerSideApplyStrategy) Op() ClusterChangeApplyStrategyOp {
+	return updateStrategyServerSideApplyAnnValue
+}
+
+func (c UpdateServerSideApplyStrategy) Apply() error {
+	// Send applied resource (instead of rebased one) so that
+	// fields set by other controllers do not become owned by kapp
+	opts := ctlres.PatchOpts{
+		FieldManager: c.aou.opts.ServerSideApplyFieldManager,
+		Force:        c.aou.opts.ServerSideApplyForceConflicts,
+	}
+
+	updatedRes, err := c.aou.identifiedResources.ServerSideApply(c.aou.change.AppliedResource(), opts)
+	if err != nil {
+		return c.conflictsErr(err)
+	}
+
+	return c.aou.recordAppliedResource(updatedRes)
+}
+
+func (c UpdateServerSideApplyStrategy) conflictsErr(err error) error {
+	if !errors.IsConflict(err) {
+		return err
+	}
+
+	var conflicts []string
+
+	if typedErr, ok := err.(errors.APIStatus); ok && typedErr.Status().Details != nil {
+		for _, cause := range typedErr.Status().Details.Causes {
+			if cause.Type == metav1.CauseTypeFieldManagerConflict {
+				conflicts = append(conflicts, fmt.Sprintf("- %s: %s", cause.Field, cause.Message))
+			}
+		}
+	}
+
+	if len(conflicts) == 0 {
+		return err
+	}
+
+	return fmt.Errorf("Server-side apply conflicts with other field managers "+
+		"(hint: remove fields from configuration or use --apply-server-side-force-conflicts):\n%s\n%w",
+		strings.Join(conflicts, "\n"), err)
+}
+
+type UpdateThreeWayMergeStrategy struct {
+	aou AddOrUpdateChange
+}
+
+func (c UpdateThreeWayMergeStrategy) Op() ClusterChangeApplyStrategyOp {
+	return updateStrategyThreeWayMergeAnnValue
+}
+
+func (c UpdateThreeWayMergeStrategy) Apply() error {
+	patchType, patch, err := c.patch()
+	if err != nil {
+		return err
+	}
+
+	updatedRes, err := c.aou.identifiedResources.Patch(c.currentResource(), patchType, patch, ctlres.PatchOpts{})
+	if err != nil {
+		return err
+	}
+
+	return c.aou.recordAppliedResource(updatedRes)
+}
+
+// patch is calculated against resource as it's currently found in the cluster
+// (instead of rebased existing resource) so that fields set by other controllers are left as is
+func (c UpdateThreeWayMergeStrategy) patch() (types.PatchType, []byte, error) {
+	currentRes := c.currentResource()
+
+	// Last applied resource is used to determine which fields
+	// were removed from configuration; it's nil if not recorded
+	// in which case no fields are removed. It's expected that resource
+	// was changed since it was applied, hence it's not verified against it.
+	lastAppliedRes := c.aou.changeFactory.NewResourceWithHistory(currentRes).RecordedLastAppliedResource()
+
+	return ctlres.NewThreeWayMergePatch(lastAppliedRes, c.aou.change.AppliedResource(), currentRes)
+}
+
+func (c UpdateThreeWayMergeStrategy) currentResource() ctlres.Resource {
+	if res := c.aou.change.ClusterOriginalResource(); res != nil {
+		return res
+	}
+	return c.aou.change.ExistingResource()
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"encoding/json"
+	"testing"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestUpdateThreeWayMergeStrategyPatch(t *testing.T) {
+	lastApplied := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"ns"},"data":{"key1":"value1","key3":"value3"}}`
+
+	// Live resource was changed by another controller (key2) since it was
+	// last applied, hence its last applied diff md5 no longer matches
+	clusterRes := mustNewTestResource(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: ns
+  annotations:
+    kapp.k14s.io/original: '`+lastApplied+`'
+    kapp.k14s.io/original-diff-md5: stale
+data:
+  key1: value1
+  key2: value2
+  key3: value3
+`)
+
+	appliedRes := mustNewTestResource(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: ns
+data:
+  key1: value1-updated
+`)
+
+	// Existing resource is rebased and stripped of history
+	existingRes, err := ctldiff.NewResourceWithoutHistory(clusterRes, nil).Resource()
+	require.NoError(t, err)
+
+	t.Run("removes fields removed from configuration and keeps fields set by others", func(t *testing.T) {
+		patchType, patch := threeWayMergePatch(t, existingRes, appliedRes, clusterRes)
+
+		require.Equal(t, types.StrategicMergePatchType, patchType)
+		require.Equal(t, map[string]interface{}{
+			"data": map[string]interface{}{"key1": "value1-updated", "key3": nil},
+		}, patch)
+	})
+
+	t.Run("does not remove fields when last applied resource is not recorded", func(t *testing.T) {
+		clusterResWithoutHistory := existingRes.DeepCopy()
+
+		_, patch := threeWayMergePatch(t, existingRes, appliedRes, clusterResWithoutHistory)
+
+		require.Equal(t, map[string]interface{}{
+			"data": map[string]interface{}{"key1": "value1-updated"},
+		}, patch)
+	})
+}
+
+func threeWayMergePatch(t *testing.T, existingRes, appliedRes, clusterRes ctlres.Resource) (types.PatchType, map[string]interface{}) {
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{})
+
+	aou := AddOrUpdateChange{
+		change:        ctldiff.NewChange(existingRes, appliedRes, appliedRes, clusterRes, ctldiff.ChangeOpts{}),
+		changeFactory: changeFactory,
+	}
+
+	patchType, patchBytes, err := UpdateThreeWayMergeStrategy{aou}.patch()
+	require.NoError(t, err)
+
+	var patch map[string]interface{}
+	require.NoError(t, json.Unmarshal(patchBytes, &patch))
+
+	return patchType, patch
+}
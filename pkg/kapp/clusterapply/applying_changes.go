@@ -4,6 +4,7 @@
 package clusterapply
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -38,12 +39,17 @@ type applyResult struct {
 	Err           error
 }
 
-func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange, []string, error) {
+func (c *ApplyingChanges) Apply(ctx context.Context, allChanges []*ctldgraph.Change) ([]WaitingChange, []string, error) {
 	startTime := time.Now()
 
 	var unsuccessfulChangeDesc []string
 
 	for {
+		// Do not start applying new changes once cancelled
+		if err := ctx.Err(); err != nil {
+			return nil, unsuccessfulChangeDesc, fmt.Errorf("Applying changes: %w", err)
+		}
+
 		nonAppliedChanges := c.nonAppliedChanges(allChanges)
 		if len(nonAppliedChanges) == 0 {
 			// Do not print applying message if no changes
@@ -69,6 +75,13 @@ func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange
 				defer applyThrottle.Done()
 
 				clusterChange := change.Change.(wrappedClusterChange).ClusterChange
+
+				// Changes waiting on throttle should not be started once cancelled
+				if err := ctx.Err(); err != nil {
+					applyCh <- applyResult{Change: change, ClusterChange: clusterChange, Err: err}
+					return
+				}
+
 				retryable, descMsgs, err := clusterChange.Apply()
 
 				applyCh <- applyResult{
@@ -102,7 +115,7 @@ func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange
 			}
 
 			c.markApplied(result.Change)
-			appliedChanges = append(appliedChanges, WaitingChange{result.Change, result.ClusterChange, time.Now()})
+			appliedChanges = append(appliedChanges, WaitingChange{Graph: result.Change, Cluster: result.ClusterChange, startTime: time.Now()})
 		}
 
 		if len(appliedChanges) > 0 {
@@ -113,7 +126,10 @@ func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange
 			return nil, unsuccessfulChangeDesc, fmt.Errorf("Timed out waiting after %s: Last error: %s", c.opts.Timeout, lastErr)
 		}
 
-		time.Sleep(c.opts.CheckInterval)
+		select {
+		case <-time.After(c.opts.CheckInterval):
+		case <-ctx.Done():
+		}
 	}
 }
 
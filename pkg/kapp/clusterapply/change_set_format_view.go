@@ -0,0 +1,178 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	"github.com/cppforlife/go-cli-ui/ui"
+)
+
+const (
+	ChangeSetFormatJSON      = "json"
+	ChangeSetFormatJSONPatch = "json-patch"
+	ChangeSetFormatUnified   = "unified"
+
+	changeSetFormatVersion = "kapp.k14s.io/v1alpha1/Changes"
+)
+
+var (
+	ChangeSetFormats = []string{ChangeSetFormatJSON, ChangeSetFormatJSONPatch, ChangeSetFormatUnified}
+)
+
+// ChangeSetFormatted is a stable representation of changes
+// meant to be consumed by other tools (json and json-patch formats)
+type ChangeSetFormatted struct {
+	Version string            `json:"version"`
+	Changes []ChangeFormatted `json:"changes"`
+}
+
+type ChangeFormatted struct {
+	Resource    ChangeFormattedResource `json:"resource"`
+	Description string                  `json:"description"`
+
+	ApplyOp         string `json:"applyOp"`
+	ApplyStrategyOp string `json:"applyStrategyOp,omitempty"`
+	WaitOp          string `json:"waitOp"`
+
+	// Only one of OpsDiff or JSONPatch is included based on format
+	OpsDiff   []ctldiff.OpsDiffOperation   `json:"opsDiff,omitempty"`
+	JSONPatch []ctldiff.JSONPatchOperation `json:"jsonPatch,omitempty"`
+	TextDiff  string                       `json:"textDiff"`
+}
+
+type ChangeFormattedResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+type ChangeSetFormatView struct {
+	changeViews []ChangeView
+	maskRules   []ctlconf.DiffMaskRule
+	opts        ChangeSetViewOpts
+}
+
+func NewChangeSetFormatView(changeViews []ChangeView,
+	maskRules []ctlconf.DiffMaskRule, opts ChangeSetViewOpts) ChangeSetFormatView {
+
+	return ChangeSetFormatView{changeViews, maskRules, opts}
+}
+
+func (v ChangeSetFormatView) Print(ui ui.UI) error {
+	bs, err := v.Bytes()
+	if err != nil {
+		return err
+	}
+	ui.PrintBlock(bs)
+	return nil
+}
+
+func (v ChangeSetFormatView) WriteToFile(path string) error {
+	bs, err := v.Bytes()
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(path, bs, 0600)
+	if err != nil {
+		return fmt.Errorf("Writing changes to '%s': %w", path, err)
+	}
+
+	return nil
+}
+
+// Bytes returns changes in configured format (json by default)
+func (v ChangeSetFormatView) Bytes() ([]byte, error) {
+	changeViews := append([]ChangeView{}, v.changeViews...)
+
+	sort.SliceStable(changeViews, func(i, j int) bool {
+		return changeViews[i].Resource().Description() < changeViews[j].Resource().Description()
+	})
+
+	if v.opts.Format == ChangeSetFormatUnified {
+		var sb strings.Builder
+		for _, view := range changeViews {
+			textDiff, err := v.textDiff(view)
+			if err != nil {
+				return nil, err
+			}
+			desc := view.Resource().Description()
+			sb.WriteString(textDiff.UnifiedString("a/"+desc, "b/"+desc, v.opts.Context))
+		}
+		return []byte(sb.String()), nil
+	}
+
+	changeSet := ChangeSetFormatted{Version: changeSetFormatVersion, Changes: []ChangeFormatted{}}
+
+	for _, view := range changeViews {
+		change, err := v.change(view)
+		if err != nil {
+			return nil, err
+		}
+		changeSet.Changes = append(changeSet.Changes, change)
+	}
+
+	bs, err := json.MarshalIndent(changeSet, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("Marshaling changes: %w", err)
+	}
+
+	return append(bs, '\n'), nil
+}
+
+func (v ChangeSetFormatView) change(view ChangeView) (ChangeFormatted, error) {
+	res := view.Resource()
+
+	strategy, err := view.ApplyStrategyOp()
+	if err != nil {
+		return ChangeFormatted{}, err
+	}
+
+	textDiff, err := v.textDiff(view)
+	if err != nil {
+		return ChangeFormatted{}, err
+	}
+
+	opsDiff, err := view.ConfigurableTextDiff().OpsDiff(v.maskRules, v.opts.Mask)
+	if err != nil {
+		return ChangeFormatted{}, err
+	}
+
+	change := ChangeFormatted{
+		Resource: ChangeFormattedResource{
+			APIVersion: res.APIVersion(),
+			Kind:       res.Kind(),
+			Namespace:  res.Namespace(),
+			Name:       res.Name(),
+		},
+		Description:     res.Description(),
+		ApplyOp:         applyOpCodeUI[view.ApplyOp()],
+		ApplyStrategyOp: string(strategy),
+		WaitOp:          waitOpCodeUI[view.WaitOp()],
+		TextDiff:        textDiff.UnifiedString("a/"+res.Description(), "b/"+res.Description(), v.opts.Context),
+	}
+
+	if v.opts.Format == ChangeSetFormatJSONPatch {
+		change.JSONPatch = opsDiff.JSONPatch()
+	} else {
+		change.OpsDiff = opsDiff.Operations()
+	}
+
+	return change, nil
+}
+
+func (v ChangeSetFormatView) textDiff(view ChangeView) (ctldiff.TextDiff, error) {
+	if v.opts.Mask {
+		return view.ConfigurableTextDiff().Masked(v.maskRules)
+	}
+	return view.ConfigurableTextDiff().Full(), nil
+}
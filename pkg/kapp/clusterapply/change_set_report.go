@@ -0,0 +1,66 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"sort"
+
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	ctldiffui "carvel.dev/kapp/pkg/kapp/diffui"
+)
+
+// NewChangeSetReportOpts collects changes within the graph for diff report
+// and diff UI (graph changes hold cluster changes, hence could be viewed)
+func NewChangeSetReportOpts(title string, graph *ctldgraph.ChangeGraph,
+	maskRules []ctlconf.DiffMaskRule, opts ChangeSetViewOpts) (ctldiffui.ReportOpts, error) {
+
+	graphChanges := append([]*ctldgraph.Change{}, graph.All()...)
+
+	sort.SliceStable(graphChanges, func(i, j int) bool {
+		return graphChanges[i].Change.Resource().Description() < graphChanges[j].Change.Resource().Description()
+	})
+
+	countsView := NewChangesCountsView()
+	reportOpts := ctldiffui.ReportOpts{Title: title, Graph: graph}
+
+	for _, graphChange := range graphChanges {
+		view, ok := graphChange.Change.(ChangeView)
+		if !ok {
+			continue
+		}
+
+		res := view.Resource()
+
+		strategy, err := view.ApplyStrategyOp()
+		if err != nil {
+			return ctldiffui.ReportOpts{}, err
+		}
+
+		diffLines, err := ctldiff.NewTextDiffView(view.ConfigurableTextDiff(), maskRules, opts.TextDiffViewOpts).Lines()
+		if err != nil {
+			return ctldiffui.ReportOpts{}, err
+		}
+
+		reportOpts.Changes = append(reportOpts.Changes, ctldiffui.ReportChange{
+			Change:      graphChange.Change,
+			Namespace:   res.Namespace(),
+			Name:        res.Name(),
+			Kind:        res.Kind(),
+			Version:     res.APIVersion(),
+			Op:          applyOpCodeUI[view.ApplyOp()],
+			OpStrategy:  string(strategy),
+			WaitOp:      waitOpCodeUI[view.WaitOp()],
+			Description: res.Description(),
+			DiffLines:   diffLines,
+		})
+
+		countsView.Add(view.ApplyOp(), view.WaitOp())
+	}
+
+	reportOpts.Notes = countsView.Strings(false)
+
+	return reportOpts, nil
+}
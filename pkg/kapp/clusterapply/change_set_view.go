@@ -18,40 +18,87 @@ type ChangeSetViewOpts struct {
 	Summary     bool
 	Changes     bool
 	ChangesYAML bool
+	// Semantic shows field level changes for updated resources
+	// (list items are matched by configured merge keys)
+	Semantic bool
+	// Format prints changes in one of ChangeSetFormats
+	// instead of text diff and summary table
+	Format string
+	// FormatOutput is a path to write formatted changes to
+	// so that they are not mixed with the rest of the output
+	FormatOutput string
 	ctldiff.TextDiffViewOpts
 }
 
 type ChangeSetView struct {
-	changeViews []ChangeView
-	maskRules   []ctlconf.DiffMaskRule
-	opts        ChangeSetViewOpts
+	changeViews   []ChangeView
+	maskRules     []ctlconf.DiffMaskRule
+	mergeKeyRules []ctlconf.DiffMergeKeyRule
+	riskRules     []ctlconf.RiskRule
+	opts          ChangeSetViewOpts
 
 	changesView *ChangesView
 }
 
-func NewChangeSetView(changeViews []ChangeView,
-	maskRules []ctlconf.DiffMaskRule, opts ChangeSetViewOpts) *ChangeSetView {
+func NewChangeSetView(changeViews []ChangeView, maskRules []ctlconf.DiffMaskRule,
+	mergeKeyRules []ctlconf.DiffMergeKeyRule, riskRules []ctlconf.RiskRule, opts ChangeSetViewOpts) *ChangeSetView {
 
-	return &ChangeSetView{changeViews, maskRules, opts, nil}
+	return &ChangeSetView{changeViews, maskRules, mergeKeyRules, riskRules, opts, nil}
 }
 
-func (v *ChangeSetView) Print(ui ui.UI) {
+func (v *ChangeSetView) Print(ui ui.UI) error {
+	v.changesView = &ChangesView{ChangeViews: v.changeViews, Sort: true,
+		RiskClassifier: NewRiskClassifier(v.riskRules), countsView: NewChangesCountsView()}
+
+	if len(v.opts.Format) > 0 || len(v.opts.FormatOutput) > 0 {
+		formatView := NewChangeSetFormatView(v.changeViews, v.maskRules, v.opts)
+
+		if len(v.opts.FormatOutput) > 0 {
+			err := formatView.WriteToFile(v.opts.FormatOutput)
+			if err != nil {
+				return err
+			}
+			ui.PrintLinef("Wrote changes to '%s'", v.opts.FormatOutput)
+		} else {
+			err := formatView.Print(ui)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Summary is still used by callers even though it's not printed
+		for _, view := range v.changeViews {
+			v.changesView.countsView.Add(view.ApplyOp(), view.WaitOp())
+		}
+		return nil
+	}
+
 	if v.opts.ChangesYAML {
 		v.printChangesYAML(ui)
 	}
 	if v.opts.Changes {
 		for _, view := range v.changeViews {
-			textDiffView := ctldiff.NewTextDiffView(view.ConfigurableTextDiff(), v.maskRules, v.opts.TextDiffViewOpts)
 			ui.BeginLinef("@@ %s %s @@\n", applyOpCodeUI[view.ApplyOp()], view.Resource().Description())
+
+			if v.opts.Semantic && view.ApplyOp() == ClusterChangeApplyOpUpdate {
+				semanticDiff, err := view.ConfigurableTextDiff().SemanticDiff(v.maskRules, v.opts.Mask, v.mergeKeyRules)
+				if err != nil {
+					return err
+				}
+				ui.PrintBlock([]byte(ctldiff.NewSemanticDiffView(semanticDiff).String()))
+				continue
+			}
+
+			textDiffView := ctldiff.NewTextDiffView(view.ConfigurableTextDiff(), v.maskRules, v.opts.TextDiffViewOpts)
 			ui.PrintBlock([]byte(textDiffView.String()))
 		}
 	}
 
-	v.changesView = &ChangesView{ChangeViews: v.changeViews, Sort: true, countsView: NewChangesCountsView()}
-
 	if v.opts.Summary {
 		v.changesView.Print(ui)
 	}
+
+	return nil
 }
 
 func (v *ChangeSetView) Summary() string {
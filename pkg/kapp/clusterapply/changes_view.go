@@ -4,10 +4,12 @@
 package clusterapply
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
 	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	"github.com/cppforlife/go-cli-ui/ui"
@@ -23,11 +25,13 @@ type ChangeView interface {
 	ApplyStrategyOp() (ClusterChangeApplyStrategyOp, error)
 	WaitOp() ClusterChangeWaitOp
 	ConfigurableTextDiff() *ctldiff.ConfigurableTextDiff
+	DryRun() (bool, error)
 }
 
 type ChangesView struct {
-	ChangeViews []ChangeView
-	Sort        bool
+	ChangeViews    []ChangeView
+	Sort           bool
+	RiskClassifier RiskClassifier
 
 	countsView *ChangesCountsView
 }
@@ -45,6 +49,14 @@ func (v *ChangesView) Print(ui ui.UI) {
 	opStrategyHeader := uitable.NewHeader("Op strategy")
 	opStrategyHeader.Title = "Op st."
 
+	dryRunHeader := uitable.NewHeader("Dry run")
+	dryRunHeader.Hidden = !v.anyDryRun()
+
+	risks, anyRisk := v.risks()
+
+	riskHeader := uitable.NewHeader("Risk")
+	riskHeader.Hidden = !anyRisk
+
 	table := uitable.Table{
 		Title: "Changes",
 		// TODO do not show total number of "changes" as it may
@@ -59,9 +71,11 @@ func (v *ChangesView) Print(ui ui.UI) {
 			uitable.NewHeader("Age"),
 			uitable.NewHeader("Op"),
 			opStrategyHeader,
+			dryRunHeader,
 			uitable.NewHeader("Wait to"),
 			reconcileStateHeader,
 			reconcileInfoHeader,
+			riskHeader,
 		},
 	}
 
@@ -77,9 +91,19 @@ func (v *ChangesView) Print(ui ui.UI) {
 		table.FillFirstColumn = true
 	}
 
-	for _, view := range v.ChangeViews {
+	var dryRunErrs []string
+
+	riskCounts := map[string]int{}
+
+	for i, view := range v.ChangeViews {
 		resource := view.Resource()
 		v.countsView.Add(view.ApplyOp(), view.WaitOp())
+		riskCounts[risks[i].Level]++
+
+		dryRunPerformed, dryRunErr := view.DryRun()
+		if dryRunErr != nil {
+			dryRunErrs = append(dryRunErrs, fmt.Sprintf("%s: %s", resource.Description(), dryRunErr))
+		}
 
 		row := []uitable.Value{
 			cmdcore.NewValueNamespace(resource.Namespace()),
@@ -102,6 +126,7 @@ func (v *ChangesView) Print(ui ui.UI) {
 		row = append(row,
 			v.applyOpCode(view.ApplyOp()),
 			v.applyStrategyOpCode(view),
+			v.dryRunCode(dryRunPerformed, dryRunErr),
 			v.waitOpCode(view.WaitOp()),
 		)
 
@@ -115,12 +140,54 @@ func (v *ChangesView) Print(ui ui.UI) {
 			)
 		}
 
+		row = append(row, v.riskCode(risks[i]))
+
 		table.Rows = append(table.Rows, row)
 	}
 
 	table.Notes = append(table.Notes, v.countsView.Strings(true)...)
 
+	if anyRisk {
+		var riskStats []string
+		for i := len(ctlconf.RiskLevels) - 1; i >= 0; i-- {
+			riskStats = append(riskStats, fmt.Sprintf("%d %s", riskCounts[ctlconf.RiskLevels[i]], ctlconf.RiskLevels[i]))
+		}
+		table.Notes = append(table.Notes, "Risk:    "+strings.Join(riskStats, ", "))
+	}
+
 	ui.PrintTable(table)
+
+	for _, errMsg := range dryRunErrs {
+		ui.ErrorLinef("Dry run failed for %s", errMsg)
+	}
+}
+
+func (v *ChangesView) anyDryRun() bool {
+	for _, view := range v.ChangeViews {
+		if performed, _ := view.DryRun(); performed {
+			return true
+		}
+	}
+	return false
+}
+
+// risks returns classification for each change view
+func (v *ChangesView) risks() ([]ChangeRisk, bool) {
+	var risks []ChangeRisk
+	var anyRisk bool
+
+	for _, view := range v.ChangeViews {
+		risk, err := v.RiskClassifier.Classify(view)
+		if err != nil {
+			risk = ChangeRisk{Level: "???", Reasons: []string{err.Error()}}
+		}
+		if len(risk.Level) > 0 {
+			anyRisk = true
+		}
+		risks = append(risks, risk)
+	}
+
+	return risks, anyRisk
 }
 
 func (v *ChangesView) Summary() string { return v.countsView.String() }
@@ -146,11 +213,14 @@ var (
 			updateStrategyFallbackOnReplaceAnnValue: "fallback on replace",
 			updateStrategyAlwaysReplaceAnnValue:     "always replace",
 			updateStrategySkipAnnValue:              "skip",
+			updateStrategyServerSideApplyAnnValue:   "server-side apply",
+			updateStrategyThreeWayMergeAnnValue:     "three-way merge patch",
 		},
 
 		ClusterChangeApplyOpDelete: {
-			deleteStrategyPlainAnnValue:  "",
-			deleteStrategyOrphanAnnValue: "orphan",
+			deleteStrategyPlainAnnValue:         "",
+			deleteStrategyOrphanAnnValue:        "orphan",
+			deleteStrategyForceFinalizeAnnValue: "force finalize",
 		},
 
 		ClusterChangeApplyOpNoop: {
@@ -198,6 +268,21 @@ func (v *ChangesView) applyStrategyOpCode(view ChangeView) uitable.Value {
 	return uitable.NewValueString("???")
 }
 
+func (v *ChangesView) riskCode(risk ChangeRisk) uitable.Value {
+	return uitable.ValueFmt{V: uitable.NewValueString(risk.String()), Error: risk.AtLeast(ctlconf.RiskLevelHigh)}
+}
+
+func (v *ChangesView) dryRunCode(performed bool, err error) uitable.Value {
+	switch {
+	case err != nil:
+		return uitable.ValueFmt{V: uitable.NewValueString("fail"), Error: true}
+	case performed:
+		return uitable.NewValueString("ok")
+	default:
+		return uitable.NewValueString("")
+	}
+}
+
 func (v *ChangesView) waitOpCode(op ClusterChangeWaitOp) uitable.Value {
 	switch op {
 	case ClusterChangeWaitOpOK:
@@ -267,7 +352,7 @@ func NewValueResourceConverged(resource ctlres.Resource) ValueResourceConverged
 	convergedResFactory := NewConvergedResourceFactory(nil, ConvergedResourceFactoryOpts{})
 
 	// TODO state vs err vs output
-	state, _, err := convergedResFactory.New(resource, nil).IsDoneApplying()
+	state, _, err := convergedResFactory.New(resource, nil).IsDoneApplying(context.Background())
 	stateUI := NewDoneApplyStateUI(state, err)
 
 	stateVal := uitable.ValueFmt{V: uitable.NewValueString(stateUI.State), Error: stateUI.Error}
@@ -4,6 +4,7 @@
 package clusterapply
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -54,6 +55,7 @@ type ClusterChangeOpts struct {
 	WaitIgnored  bool
 
 	AddOrUpdateChangeOpts
+	DeleteChangeOpts
 }
 
 type ClusterChange struct {
@@ -146,7 +148,7 @@ func (c *ClusterChange) WaitOp() ClusterChangeWaitOp {
 		// TODO associated resources
 		// If existing resource is not in a "done successful" state,
 		// indicate that this will be something we need to wait for
-		resState, _, err := c.convergedResFactory.New(c.change.ClusterOriginalResource(), nil).IsDoneApplying()
+		resState, _, err := c.convergedResFactory.New(c.change.ClusterOriginalResource(), nil).IsDoneApplying(context.Background())
 		if err != nil || !(resState.Done && resState.Successful) {
 			return ClusterChangeWaitOpOK
 		}
@@ -209,7 +211,7 @@ func (c *ClusterChange) applyStrategy() (ApplyStrategy, error) {
 			c.changeSetFactory, c.opts.AddOrUpdateChangeOpts, c.diffMaskRules}.ApplyStrategy()
 
 	case ClusterChangeApplyOpDelete:
-		return DeleteChange{c.change, c.identifiedResources}.ApplyStrategy()
+		return DeleteChange{c.change, c.identifiedResources, c.opts.DeleteChangeOpts, c.ui}.ApplyStrategy()
 
 	case ClusterChangeApplyOpNoop:
 		return NoopStrategy{}, nil
@@ -222,21 +224,24 @@ func (c *ClusterChange) applyStrategy() (ApplyStrategy, error) {
 	}
 }
 
-func (c *ClusterChange) IsDoneApplying() (ctlresm.DoneApplyState, []string, error) {
-	state, descMsgs, err := c.isDoneApplying()
+func (c *ClusterChange) IsDoneApplying(ctx context.Context) (ctlresm.DoneApplyState, []string, error) {
+	state, descMsgs, err := c.isDoneApplying(ctx)
+	if err == nil && state.Done && state.Successful {
+		err = c.deleteSucceededHook()
+	}
 	primaryDescMsg := fmt.Sprintf("%s: %s", NewDoneApplyStateUI(state, err).State, c.WaitDescription())
 	return state, append([]string{primaryDescMsg}, descMsgs...), err
 }
 
-func (c *ClusterChange) isDoneApplying() (ctlresm.DoneApplyState, []string, error) {
+func (c *ClusterChange) isDoneApplying(ctx context.Context) (ctlresm.DoneApplyState, []string, error) {
 	op := c.WaitOp()
 
 	switch op {
 	case ClusterChangeWaitOpOK:
-		return ReconcilingChange{c.change, c.identifiedResources, c.convergedResFactory}.IsDoneApplying()
+		return ReconcilingChange{c.change, c.identifiedResources, c.convergedResFactory}.IsDoneApplying(ctx)
 
 	case ClusterChangeWaitOpDelete:
-		return DeleteChange{c.change, c.identifiedResources}.IsDoneApplying()
+		return DeleteChange{c.change, c.identifiedResources, c.opts.DeleteChangeOpts, c.ui}.IsDoneApplying()
 
 	case ClusterChangeWaitOpNoop:
 		return ctlresm.DoneApplyState{Done: true, Successful: true}, nil, nil
@@ -246,6 +251,32 @@ func (c *ClusterChange) isDoneApplying() (ctlresm.DoneApplyState, []string, erro
 	}
 }
 
+// deleteSucceededHook deletes hook resource once it has
+// successfully completed if its delete policy asks for it
+func (c *ClusterChange) deleteSucceededHook() error {
+	if c.WaitOp() != ClusterChangeWaitOpOK {
+		return nil
+	}
+
+	res := c.change.NewResource()
+
+	hook, isHook, err := ctlres.NewHook(res)
+	if err != nil || !isHook {
+		return err
+	}
+
+	if hook.DeletePolicy != ctlres.HookDeletePolicyHookSucceeded {
+		return nil
+	}
+
+	err = c.identifiedResources.Delete(res)
+	if err != nil {
+		return fmt.Errorf("Deleting succeeded hook %s: %w", res.Description(), err)
+	}
+
+	return nil
+}
+
 func (c *ClusterChange) ApplyDescription() string {
 	return fmt.Sprintf("%s %s", applyOpCodeUI[c.ApplyOp()], c.change.NewOrExistingResource().Description())
 }
@@ -260,10 +291,19 @@ func (c *ClusterChange) ClusterOriginalResource() ctlres.Resource {
 	return c.change.ClusterOriginalResource()
 }
 
+func (c *ClusterChange) OpsDiff() ctldiff.OpsDiff { return c.change.OpsDiff() }
+
 func (c *ClusterChange) ConfigurableTextDiff() *ctldiff.ConfigurableTextDiff {
 	return c.change.ConfigurableTextDiff()
 }
 
+func (c *ClusterChange) DryRun() (bool, error) {
+	if dryRunChange, ok := c.change.(DryRunChange); ok {
+		return dryRunChange.DryRun()
+	}
+	return false, nil
+}
+
 func (c *ClusterChange) applyErr(err error) error {
 	if err == nil {
 		return nil
@@ -4,6 +4,7 @@
 package clusterapply
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -12,6 +13,7 @@ import (
 	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
 	"carvel.dev/kapp/pkg/kapp/logger"
 	uierrs "github.com/cppforlife/go-cli-ui/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 type ClusterChangeSetOpts struct {
@@ -93,7 +95,26 @@ func (c ClusterChangeSet) markChangesToWait(change *ctldgraph.Change) bool {
 	return change.Change.(wrappedClusterChange).WaitOp() != ClusterChangeWaitOpNoop
 }
 
-func (c ClusterChangeSet) Apply(changesGraph *ctldgraph.ChangeGraph) error {
+func (c ClusterChangeSet) waitingGKs(changesGraph *ctldgraph.ChangeGraph) []schema.GroupKind {
+	var gks []schema.GroupKind
+	seenGKs := map[schema.GroupKind]struct{}{}
+
+	for _, change := range changesGraph.All() {
+		clusterChange := change.Change.(wrappedClusterChange).ClusterChange
+		if clusterChange.WaitOp() == ClusterChangeWaitOpNoop {
+			continue
+		}
+		gk := clusterChange.Resource().GroupKind()
+		if _, found := seenGKs[gk]; !found {
+			seenGKs[gk] = struct{}{}
+			gks = append(gks, gk)
+		}
+	}
+
+	return gks
+}
+
+func (c ClusterChangeSet) Apply(ctx context.Context, changesGraph *ctldgraph.ChangeGraph) error {
 	defer c.logger.DebugFunc("Apply").Finish()
 
 	expectedNumChanges := len(changesGraph.All())
@@ -103,10 +124,13 @@ func (c ClusterChangeSet) Apply(changesGraph *ctldgraph.ChangeGraph) error {
 		expectedNumChanges, c.opts.ApplyingChangesOpts, c.clusterChangeFactory, c.ui, c.opts.ExitEarlyOnApplyError)
 	waitingChanges := NewWaitingChanges(expectedNumChanges, c.opts.WaitingChangesOpts, c.ui, c.opts.ExitEarlyOnWaitError)
 
+	waitingChanges.StartWatching(c.waitingGKs(changesGraph))
+	defer waitingChanges.StopWatching()
+
 	var unsuccessfulChanges []string
 
 	for {
-		appliedChanges, unsuccessfulChangeDesc, err := applyingChanges.Apply(blockedChanges.Unblocked())
+		appliedChanges, unsuccessfulChangeDesc, err := applyingChanges.Apply(ctx, blockedChanges.Unblocked())
 		if err != nil {
 			return err
 		}
@@ -133,7 +157,7 @@ func (c ClusterChangeSet) Apply(changesGraph *ctldgraph.ChangeGraph) error {
 			return waitingChanges.Complete()
 		}
 
-		doneChanges, unsuccessfulChangeDesc, err := waitingChanges.WaitForAny()
+		doneChanges, unsuccessfulChangeDesc, err := waitingChanges.WaitForAny(ctx)
 		if err != nil {
 			return err
 		}
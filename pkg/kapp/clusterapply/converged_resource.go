@@ -4,6 +4,7 @@
 package clusterapply
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"sort"
@@ -31,9 +32,14 @@ func NewConvergedResource(res ctlres.Resource,
 	return ConvergedResource{res, associatedRsFunc, specificResFactories}
 }
 
-func (c ConvergedResource) IsDoneApplying() (ctlresm.DoneApplyState, []string, error) {
+func (c ConvergedResource) IsDoneApplying(ctx context.Context) (ctlresm.DoneApplyState, []string, error) {
 	var descMsgs []string
 
+	// Avoid querying for associated resources once waiting was cancelled
+	if err := ctx.Err(); err != nil {
+		return ctlresm.DoneApplyState{}, nil, err
+	}
+
 	associatedRs, err := c.associatedRs()
 	if err != nil {
 		return ctlresm.DoneApplyState{}, nil, err
@@ -7,10 +7,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -19,6 +21,9 @@ const (
 	deleteStrategyAnnKey                                      = "kapp.k14s.io/delete-strategy"
 	deleteStrategyPlainAnnValue  ClusterChangeApplyStrategyOp = ""
 	deleteStrategyOrphanAnnValue ClusterChangeApplyStrategyOp = "orphan"
+	// Resource is deleted, and if it's still waiting on finalizers after
+	// force finalize grace period, its finalizers are removed
+	deleteStrategyForceFinalizeAnnValue ClusterChangeApplyStrategyOp = "force-finalize"
 
 	appLabelKey      = "kapp.k14s.io/app" // TODO duplicated here
 	orphanedLabelKey = "kapp.k14s.io/orphaned"
@@ -28,9 +33,17 @@ var (
 	jsonPointerEncoder = strings.NewReplacer("~", "~0", "/", "~1")
 )
 
+type DeleteChangeOpts struct {
+	// ForceFinalizeGracePeriod is amount of time resource with force-finalize
+	// delete strategy is allowed to wait on its finalizers
+	ForceFinalizeGracePeriod time.Duration
+}
+
 type DeleteChange struct {
 	change              ctldiff.Change
 	identifiedResources ctlres.IdentifiedResources
+	opts                DeleteChangeOpts
+	ui                  UI
 }
 
 type inoperableResourceRef struct {
@@ -73,6 +86,9 @@ func (c DeleteChange) ApplyStrategy() (ApplyStrategy, error) {
 	case deleteStrategyOrphanAnnValue:
 		return DeleteOrphanStrategy{res, c}, nil
 
+	case deleteStrategyForceFinalizeAnnValue:
+		return DeleteForceFinalizeStrategy{res, c}, nil
+
 	default:
 		return nil, fmt.Errorf("Unknown delete strategy: %s", strategy)
 	}
@@ -103,9 +119,57 @@ func (c DeleteChange) IsDoneApplying() (ctlresm.DoneApplyState, []string, error)
 		return ctlresm.DoneApplyState{Done: true, Successful: true}, nil, nil
 	}
 
+	if ClusterChangeApplyStrategyOp(res.Annotations()[deleteStrategyAnnKey]) == deleteStrategyForceFinalizeAnnValue {
+		finalized, err := c.forceFinalize(existingRes)
+		if err != nil {
+			return ctlresm.DoneApplyState{}, nil, err
+		}
+		if finalized {
+			return ctlresm.DoneApplyState{Done: false, Successful: true}, []string{uiWaitMsgPrefix + "Removed finalizers"}, nil
+		}
+	}
+
 	return ctlresm.DoneApplyState{Done: false, Successful: true}, descMessage(existingRes), nil
 }
 
+// forceFinalize removes finalizers from a resource that has been
+// waiting on them for longer than force finalize grace period
+func (c DeleteChange) forceFinalize(res ctlres.Resource) (bool, error) {
+	if !res.IsDeleting() || len(res.Finalizers()) == 0 {
+		return false, nil
+	}
+
+	waitingFor := time.Since(res.DeletingAt())
+	if waitingFor < c.opts.ForceFinalizeGracePeriod {
+		return false, nil
+	}
+
+	patchJSON, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers": nil,
+			// Fail patch if resource was recreated in the meantime
+			"uid": res.UID(),
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	_, err = c.identifiedResources.Patch(res, types.MergePatchType, patchJSON, ctlres.PatchOpts{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("Removing finalizers from %s: %w", res.Description(), err)
+	}
+
+	c.ui.Notify([]string{fmt.Sprintf("WARNING: Forcefully removed finalizers (%s) from %s "+
+		"after waiting on them for %s (delete strategy: %s)", strings.Join(res.Finalizers(), ", "),
+		res.Description(), waitingFor.Round(time.Second), deleteStrategyForceFinalizeAnnValue)})
+
+	return true, nil
+}
+
 type DeletePlainStrategy struct {
 	res ctlres.Resource
 	d   DeleteChange
@@ -119,6 +183,20 @@ func (c DeletePlainStrategy) Apply() error {
 	return c.d.identifiedResources.Delete(c.res)
 }
 
+type DeleteForceFinalizeStrategy struct {
+	res ctlres.Resource
+	d   DeleteChange
+}
+
+func (c DeleteForceFinalizeStrategy) Op() ClusterChangeApplyStrategyOp {
+	return deleteStrategyForceFinalizeAnnValue
+}
+
+func (c DeleteForceFinalizeStrategy) Apply() error {
+	// Finalizers are removed (if necessary) while waiting for resource to be deleted
+	return c.d.identifiedResources.Delete(c.res)
+}
+
 type DeleteOrphanStrategy struct {
 	res ctlres.Resource
 	d   DeleteChange
@@ -146,7 +224,7 @@ func (c DeleteOrphanStrategy) Apply() error {
 		return err
 	}
 
-	_, err = c.d.identifiedResources.Patch(c.res, types.JSONPatchType, patchJSON)
+	_, err = c.d.identifiedResources.Patch(c.res, types.JSONPatchType, patchJSON, ctlres.PatchOpts{})
 	return err
 }
 
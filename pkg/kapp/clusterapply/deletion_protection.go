@@ -0,0 +1,97 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+const (
+	preventDeleteAnnKey = "kapp.k14s.io/prevent-delete" // valid value is ''
+)
+
+// DeletionProtection prevents deletion of resources that are
+// either matched by deletion protection rules or are annotated
+// with kapp.k14s.io/prevent-delete, unless they are explicitly allowed.
+type DeletionProtection struct {
+	ResourceMatchers []ctlres.ResourceMatcher
+	// AllowedResources are formatted as <kind>.<group>/<namespace>/<name>
+	// (or <kind>.<group>/<name> for cluster scoped resources);
+	// group is omitted for core resources (e.g. ConfigMap/ns/name)
+	AllowedResources []string
+}
+
+func (p DeletionProtection) Check(graph *ctldgraph.ChangeGraph) error {
+	allowed := map[string]struct{}{}
+	for _, ref := range p.AllowedResources {
+		allowed[ref] = struct{}{}
+	}
+
+	var protectedRefs []string
+
+	for _, change := range graph.All() {
+		wrappedChange, ok := change.Change.(wrappedClusterChange)
+		if !ok {
+			return fmt.Errorf("Expected change '%s' to be cluster change", change.Change.Resource().Description())
+		}
+
+		clusterChange := wrappedChange.ClusterChange
+		if clusterChange.ApplyOp() != ClusterChangeApplyOpDelete {
+			continue
+		}
+
+		strategyOp, err := clusterChange.ApplyStrategyOp()
+		if err != nil {
+			return err
+		}
+		if strategyOp == deleteStrategyOrphanAnnValue {
+			continue
+		}
+
+		res := clusterChange.Resource()
+		if !p.isProtected(res) {
+			continue
+		}
+
+		ref := deletionProtectionRef(res)
+		if _, found := allowed[ref]; !found {
+			protectedRefs = append(protectedRefs, ref)
+		}
+	}
+
+	if len(protectedRefs) > 0 {
+		sort.Strings(protectedRefs)
+		return fmt.Errorf("Refusing to delete %d protected resource(s): %s "+
+			"(hint: allow deletion of each resource via --dangerous-allow-deleting-protected-resource)",
+			len(protectedRefs), strings.Join(protectedRefs, ", "))
+	}
+
+	return nil
+}
+
+func (p DeletionProtection) isProtected(res ctlres.Resource) bool {
+	if _, found := res.Annotations()[preventDeleteAnnKey]; found {
+		return true
+	}
+	for _, matcher := range p.ResourceMatchers {
+		if matcher.Matches(res) {
+			return true
+		}
+	}
+	return false
+}
+
+func deletionProtectionRef(res ctlres.Resource) string {
+	// Include group since kinds are not unique across API groups
+	gk := res.GroupKind().String()
+	if len(res.Namespace()) == 0 {
+		return gk + "/" + res.Name()
+	}
+	return gk + "/" + res.Namespace() + "/" + res.Name()
+}
@@ -0,0 +1,246 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"sync"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
+	"carvel.dev/kapp/pkg/kapp/util"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type DryRunChangesOpts struct {
+	Concurrency int
+
+	AddOrUpdateChangeOpts
+}
+
+// DryRunChanges sends add and update changes to the API server
+// as dry run requests so that admission webhook mutations and rejections
+// are visible before any change is actually applied.
+type DryRunChanges struct {
+	opts                DryRunChangesOpts
+	identifiedResources ctlres.IdentifiedResources
+	changeFactory       ctldiff.ChangeFactory
+}
+
+func NewDryRunChanges(opts DryRunChangesOpts, identifiedResources ctlres.IdentifiedResources,
+	changeFactory ctldiff.ChangeFactory) DryRunChanges {
+
+	return DryRunChanges{opts, identifiedResources, changeFactory}
+}
+
+func (c DryRunChanges) Apply(changes []ctldiff.Change) ([]ctldiff.Change, error) {
+	result := make([]ctldiff.Change, len(changes))
+	errs := make([]error, len(changes))
+
+	added, err := newDryRunAddedResources(changes)
+	if err != nil {
+		return nil, err
+	}
+
+	throttle := util.NewThrottle(c.opts.Concurrency)
+	var wg sync.WaitGroup
+
+	for i, change := range changes {
+		i, change := i, change // copy
+		wg.Add(1)
+
+		go func() {
+			throttle.Take()
+			defer throttle.Done()
+			defer wg.Done()
+
+			result[i], errs[i] = c.dryRun(change, added)
+		}()
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (c DryRunChanges) dryRun(change ctldiff.Change, added dryRunAddedResources) (ctldiff.Change, error) {
+	var dryRunRes ctlres.Resource
+	var err error
+
+	switch change.Op() {
+	case ctldiff.ChangeOpAdd:
+		dryRunRes, err = c.identifiedResources.CreateDryRun(change.NewResource())
+		if err != nil {
+			// Create strategies that fallback on update expect resource to be present
+			strategy := change.NewResource().Annotations()[createStrategyAnnKey]
+			if errors.IsAlreadyExists(err) && ClusterChangeApplyStrategyOp(strategy) != createStrategyPlainAnnValue {
+				return DryRunChange{Change: change}, nil
+			}
+			// Namespaces and CRDs added by the same deploy do not exist yet
+			if added.IsDependencyErr(change.NewResource(), err) {
+				return DryRunChange{Change: change}, nil
+			}
+			return DryRunChange{Change: change, performed: true, err: err}, nil
+		}
+
+	case ctldiff.ChangeOpUpdate:
+		strategy, found := change.NewResource().Annotations()[updateStrategyAnnKey]
+		if !found {
+			strategy = c.opts.DefaultUpdateStrategy
+		}
+
+		switch ClusterChangeApplyStrategyOp(strategy) {
+		case updateStrategySkipAnnValue, updateStrategyAlwaysReplaceAnnValue:
+			return DryRunChange{Change: change}, nil
+
+		case updateStrategyServerSideApplyAnnValue:
+			dryRunRes, err = c.identifiedResources.ServerSideApply(change.AppliedResource(), ctlres.PatchOpts{
+				FieldManager: c.opts.ServerSideApplyFieldManager,
+				Force:        c.opts.ServerSideApplyForceConflicts,
+				DryRun:       true,
+			})
+
+		default:
+			dryRunRes, err = c.identifiedResources.UpdateDryRun(change.NewResource())
+			if err != nil && errors.IsInvalid(err) &&
+				ClusterChangeApplyStrategyOp(strategy) == updateStrategyFallbackOnReplaceAnnValue {
+				return DryRunChange{Change: change}, nil
+			}
+		}
+		if err != nil {
+			return DryRunChange{Change: change, performed: true, err: err}, nil
+		}
+
+	default:
+		return DryRunChange{Change: change}, nil
+	}
+
+	dryRunRes, err = ctlres.NewResourceWithManagedFields(dryRunRes, false).Resource()
+	if err != nil {
+		return nil, err
+	}
+
+	var existingRes ctlres.Resource
+	if change.Op() == ctldiff.ChangeOpUpdate {
+		// Compare against actual cluster copy since server populated
+		// defaults are present in both resources
+		existingRes = change.ClusterOriginalResource()
+	} else {
+		// Generated fields differ between dry runs hence are not part
+		// of the diff (e.g. so that deploy plans could be compared)
+		for _, mod := range dryRunGeneratedFieldsMods {
+			err := mod.Apply(dryRunRes)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	dryRunChange, err := c.changeFactory.NewExactChange(existingRes, dryRunRes)
+	if err != nil {
+		return nil, err
+	}
+
+	return DryRunChange{Change: change, dryRunChange: dryRunChange, performed: true}, nil
+}
+
+var dryRunGeneratedFieldsMods = []ctlres.FieldRemoveMod{
+	{ResourceMatcher: ctlres.AllMatcher{}, Path: ctlres.NewPathFromStrings([]string{"metadata", "uid"})},
+	{ResourceMatcher: ctlres.AllMatcher{}, Path: ctlres.NewPathFromStrings([]string{"metadata", "creationTimestamp"})},
+	{ResourceMatcher: ctlres.AllMatcher{}, Path: ctlres.NewPathFromStrings([]string{"metadata", "resourceVersion"})},
+	{ResourceMatcher: ctlres.AllMatcher{}, Path: ctlres.NewPathFromStrings([]string{"metadata", "generation"})},
+}
+
+// dryRunAddedResources keeps track of namespaces and custom resource types
+// that are added within the same change set so that dry run of resources
+// depending on them is not considered as failed
+type dryRunAddedResources struct {
+	namespaces map[string]struct{}
+	groupKinds map[schema.GroupKind]struct{}
+}
+
+func newDryRunAddedResources(changes []ctldiff.Change) (dryRunAddedResources, error) {
+	added := dryRunAddedResources{
+		namespaces: map[string]struct{}{},
+		groupKinds: map[schema.GroupKind]struct{}{},
+	}
+
+	for _, change := range changes {
+		if change.Op() != ctldiff.ChangeOpAdd {
+			continue
+		}
+
+		res := change.NewResource()
+
+		if res.APIGroup() == "" && res.Kind() == "Namespace" {
+			added.namespaces[res.Name()] = struct{}{}
+		}
+
+		if crd := ctlresm.NewAPIExtensionsVxCRD(res); crd != nil {
+			group, err := crd.Group()
+			if err != nil {
+				return dryRunAddedResources{}, err
+			}
+			kind, err := crd.Kind()
+			if err != nil {
+				return dryRunAddedResources{}, err
+			}
+			added.groupKinds[schema.GroupKind{Group: group, Kind: kind}] = struct{}{}
+		}
+	}
+
+	return added, nil
+}
+
+// IsDependencyErr returns true if dry run failed since resource's
+// namespace or type is only going to be added
+func (a dryRunAddedResources) IsDependencyErr(res ctlres.Resource, err error) bool {
+	if _, found := a.groupKinds[res.GroupKind()]; found {
+		if _, ok := err.(ctlres.ResourceTypesUnknownTypeErr); ok || errors.IsNotFound(err) {
+			return true
+		}
+	}
+	if _, found := a.namespaces[res.Namespace()]; found && len(res.Namespace()) > 0 {
+		return errors.IsNotFound(err)
+	}
+	return false
+}
+
+// DryRunChange presents server dry run result as a new resource in diffs,
+// but otherwise behaves as an original change (e.g. when being applied).
+type DryRunChange struct {
+	ctldiff.Change
+
+	dryRunChange ctldiff.Change
+	performed    bool
+	err          error
+}
+
+var _ ctldiff.Change = DryRunChange{}
+
+func (c DryRunChange) ConfigurableTextDiff() *ctldiff.ConfigurableTextDiff {
+	if c.dryRunChange != nil {
+		return c.dryRunChange.ConfigurableTextDiff()
+	}
+	return c.Change.ConfigurableTextDiff()
+}
+
+// OpsDiff matches presented diff so that deploy plans and
+// diff filters describe changes that were approved
+func (c DryRunChange) OpsDiff() ctldiff.OpsDiff {
+	if c.dryRunChange != nil {
+		return c.dryRunChange.OpsDiff()
+	}
+	return c.Change.OpsDiff()
+}
+
+// DryRun indicates if dry run was performed for this change and its error
+func (c DryRunChange) DryRun() (bool, error) { return c.performed, c.err }
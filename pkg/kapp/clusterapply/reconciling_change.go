@@ -4,6 +4,8 @@
 package clusterapply
 
 import (
+	"context"
+
 	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
 	"carvel.dev/kapp/pkg/kapp/logger"
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
@@ -20,7 +22,7 @@ type SpecificResource interface {
 	IsDoneApplying() ctlresm.DoneApplyState
 }
 
-func (c ReconcilingChange) IsDoneApplying() (ctlresm.DoneApplyState, []string, error) {
+func (c ReconcilingChange) IsDoneApplying(ctx context.Context) (ctlresm.DoneApplyState, []string, error) {
 	labeledResources := ctlres.NewLabeledResources(nil, c.identifiedResources, logger.NewTODOLogger())
 
 	// Refresh resource with latest changes from the server
@@ -31,5 +33,5 @@ func (c ReconcilingChange) IsDoneApplying() (ctlresm.DoneApplyState, []string, e
 		return ctlresm.DoneApplyState{}, nil, err
 	}
 
-	return c.convergedResFactory.New(parentRes, labeledResources.GetAssociated).IsDoneApplying()
+	return c.convergedResFactory.New(parentRes, labeledResources.GetAssociated).IsDoneApplying(ctx)
 }
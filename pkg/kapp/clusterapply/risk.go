@@ -0,0 +1,241 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+// ChangeRisk explains why change may be destructive or high-impact;
+// empty level indicates that no risk rules matched
+type ChangeRisk struct {
+	Level   string
+	Reasons []string
+}
+
+func (r ChangeRisk) AtLeast(level string) bool {
+	return len(r.Level) > 0 && slices.Index(ctlconf.RiskLevels, r.Level) >= slices.Index(ctlconf.RiskLevels, level)
+}
+
+func (r ChangeRisk) String() string {
+	if len(r.Level) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s: %s", r.Level, strings.Join(r.Reasons, ", "))
+}
+
+// RiskClassifier classifies changes based on configured risk rules
+type RiskClassifier struct {
+	rules []ctlconf.RiskRule
+}
+
+func NewRiskClassifier(rules []ctlconf.RiskRule) RiskClassifier {
+	return RiskClassifier{rules}
+}
+
+func (c RiskClassifier) Classify(view ChangeView) (ChangeRisk, error) {
+	var risk ChangeRisk
+
+	op := view.ApplyOp()
+	if op == ClusterChangeApplyOpNoop {
+		return risk, nil
+	}
+
+	strategy, err := view.ApplyStrategyOp()
+	if err != nil {
+		return ChangeRisk{}, err
+	}
+
+	for _, rule := range c.rules {
+		if !rule.ResourceMatcher().Matches(view.Resource()) {
+			continue
+		}
+		if len(rule.Ops) > 0 && !slices.Contains(rule.Ops, applyOpCodeUI[op]) {
+			continue
+		}
+		if len(rule.Strategies) > 0 && !slices.Contains(rule.Strategies, string(strategy)) {
+			continue
+		}
+		if len(rule.Paths) > 0 && !c.anyPathChanged(view, rule) {
+			continue
+		}
+
+		if !risk.AtLeast(rule.Level) {
+			risk.Level = rule.Level
+		}
+		if !slices.Contains(risk.Reasons, rule.Reason) {
+			risk.Reasons = append(risk.Reasons, rule.Reason)
+		}
+	}
+
+	return risk, nil
+}
+
+func (c RiskClassifier) anyPathChanged(view ChangeView, rule ctlconf.RiskRule) bool {
+	if view.ApplyOp() == ClusterChangeApplyOpDelete {
+		return false // fields are not set when deleting
+	}
+
+	var existingObj interface{}
+	if view.ClusterOriginalResource() != nil {
+		existingObj = view.ClusterOriginalResource().UnstructuredObject()
+	}
+	newObj := view.Resource().UnstructuredObject()
+
+	for _, path := range rule.Paths {
+		newVal, found := riskPathValue(newObj, path)
+		if !found {
+			continue // fields that are not set are typically defaulted by the cluster
+		}
+		existingVal, _ := riskPathValue(existingObj, path)
+		if rule.OnlyAdditions {
+			if !riskValueIncluded(existingVal, newVal) {
+				return true
+			}
+		} else if !reflect.DeepEqual(existingVal, newVal) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func riskPathValue(obj interface{}, path ctlres.Path) (interface{}, bool) {
+	for i, part := range path {
+		switch {
+		case part.MapKey != nil:
+			typedObj, ok := obj.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			obj, ok = typedObj[*part.MapKey]
+			if !ok {
+				return nil, false
+			}
+
+		case part.ArrayIndex != nil:
+			typedObj, ok := obj.([]interface{})
+			if !ok {
+				return nil, false
+			}
+
+			switch {
+			case part.ArrayIndex.All != nil:
+				var vals []interface{}
+				for _, item := range typedObj {
+					val, found := riskPathValue(item, path[i+1:])
+					if found {
+						vals = append(vals, val)
+					}
+				}
+				return vals, len(vals) > 0
+
+			case part.ArrayIndex.Index != nil:
+				if *part.ArrayIndex.Index >= len(typedObj) {
+					return nil, false
+				}
+				obj = typedObj[*part.ArrayIndex.Index]
+
+			default:
+				return nil, false
+			}
+
+		default:
+			return nil, false
+		}
+	}
+
+	return obj, true
+}
+
+// riskValueIncluded returns true if new value does not add anything
+// to existing value: every list item of new value has to be included
+// in some existing item, and maps have to have the same keys
+// (e.g. removing resourceNames from RBAC rule widens it)
+func riskValueIncluded(existingVal, newVal interface{}) bool {
+	switch typedNewVal := newVal.(type) {
+	case map[string]interface{}:
+		typedExistingVal, ok := existingVal.(map[string]interface{})
+		if !ok || len(typedExistingVal) != len(typedNewVal) {
+			return false
+		}
+		for key, val := range typedNewVal {
+			existingItemVal, found := typedExistingVal[key]
+			if !found || !riskValueIncluded(existingItemVal, val) {
+				return false
+			}
+		}
+		return true
+
+	case []interface{}:
+		typedExistingVal, ok := existingVal.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, val := range typedNewVal {
+			included := false
+			for _, existingItemVal := range typedExistingVal {
+				if riskValueIncluded(existingItemVal, val) {
+					included = true
+					break
+				}
+			}
+			if !included {
+				return false
+			}
+		}
+		return true
+
+	default:
+		return reflect.DeepEqual(existingVal, newVal)
+	}
+}
+
+// RiskCheck fails if any of changes is classified
+// with given risk level or higher
+type RiskCheck struct {
+	Classifier RiskClassifier
+	FailOn     string
+}
+
+func (c RiskCheck) Check(graph *ctldgraph.ChangeGraph) error {
+	if len(c.FailOn) == 0 {
+		return nil
+	}
+
+	var riskyDescs []string
+
+	for _, change := range graph.All() {
+		wrappedChange, ok := change.Change.(wrappedClusterChange)
+		if !ok {
+			return fmt.Errorf("Expected change '%s' to be cluster change", change.Change.Resource().Description())
+		}
+		clusterChange := wrappedChange.ClusterChange
+
+		risk, err := c.Classifier.Classify(clusterChange)
+		if err != nil {
+			return err
+		}
+
+		if risk.AtLeast(c.FailOn) {
+			riskyDescs = append(riskyDescs, fmt.Sprintf("%s: %s", clusterChange.Resource().Description(), risk))
+		}
+	}
+
+	if len(riskyDescs) > 0 {
+		sort.Strings(riskyDescs)
+		return fmt.Errorf("Refusing to apply %d change(s) with risk level '%s' or higher:\n- %s",
+			len(riskyDescs), c.FailOn, strings.Join(riskyDescs, "\n- "))
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"testing"
+
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRiskClassifierDefaultRBACRules(t *testing.T) {
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(nil)
+	require.NoError(t, err)
+
+	classifier := NewRiskClassifier(conf.RiskRules())
+
+	clusterRole := func(rules string) string {
+		return `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: role
+rules:
+` + rules
+	}
+
+	const readConfigMaps = `
+- apiGroups: [""]
+  resources: [configmaps]
+  verbs: [get, list]
+`
+
+	testCases := []struct {
+		description   string
+		op            ClusterChangeApplyOp
+		existing      string
+		new           string
+		expectedLevel string
+	}{
+		{
+			description: "create is not classified",
+			op:          ClusterChangeApplyOpAdd,
+			new:         clusterRole(readConfigMaps),
+		},
+		{
+			description: "removing verbs is not classified",
+			op:          ClusterChangeApplyOpUpdate,
+			existing:    clusterRole(readConfigMaps),
+			new: clusterRole(`
+- apiGroups: [""]
+  resources: [configmaps]
+  verbs: [get]
+`),
+		},
+		{
+			description: "removing rules is not classified",
+			op:          ClusterChangeApplyOpUpdate,
+			existing: clusterRole(readConfigMaps + `
+- apiGroups: [""]
+  resources: [secrets]
+  verbs: [get]
+`),
+			new: clusterRole(readConfigMaps),
+		},
+		{
+			description: "adding verbs is classified",
+			op:          ClusterChangeApplyOpUpdate,
+			existing:    clusterRole(readConfigMaps),
+			new: clusterRole(`
+- apiGroups: [""]
+  resources: [configmaps]
+  verbs: [get, list, delete]
+`),
+			expectedLevel: ctlconf.RiskLevelHigh,
+		},
+		{
+			description: "adding rules is classified",
+			op:          ClusterChangeApplyOpUpdate,
+			existing:    clusterRole(readConfigMaps),
+			new: clusterRole(readConfigMaps + `
+- apiGroups: [""]
+  resources: [secrets]
+  verbs: [get]
+`),
+			expectedLevel: ctlconf.RiskLevelHigh,
+		},
+		{
+			description: "removing resource names is classified",
+			op:          ClusterChangeApplyOpUpdate,
+			existing: clusterRole(`
+- apiGroups: [""]
+  resources: [configmaps]
+  resourceNames: [cm1]
+  verbs: [get]
+`),
+			new: clusterRole(`
+- apiGroups: [""]
+  resources: [configmaps]
+  verbs: [get]
+`),
+			expectedLevel: ctlconf.RiskLevelHigh,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			view := testRiskChangeView{op: testCase.op, res: mustNewTestResource(t, testCase.new)}
+			if len(testCase.existing) > 0 {
+				view.existingRes = mustNewTestResource(t, testCase.existing)
+			}
+
+			risk, err := classifier.Classify(view)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expectedLevel, risk.Level)
+
+			if len(testCase.expectedLevel) > 0 {
+				require.Equal(t, []string{"grants additional cluster-wide RBAC permissions"}, risk.Reasons)
+			}
+		})
+	}
+}
+
+type testRiskChangeView struct {
+	op          ClusterChangeApplyOp
+	res         ctlres.Resource
+	existingRes ctlres.Resource
+}
+
+var _ ChangeView = testRiskChangeView{}
+
+func (v testRiskChangeView) Resource() ctlres.Resource                { return v.res }
+func (v testRiskChangeView) ClusterOriginalResource() ctlres.Resource { return v.existingRes }
+func (v testRiskChangeView) ApplyOp() ClusterChangeApplyOp            { return v.op }
+func (v testRiskChangeView) ApplyStrategyOp() (ClusterChangeApplyStrategyOp, error) {
+	return "", nil
+}
+func (v testRiskChangeView) WaitOp() ClusterChangeWaitOp                         { return ClusterChangeWaitOpNoop }
+func (v testRiskChangeView) ConfigurableTextDiff() *ctldiff.ConfigurableTextDiff { return nil }
+func (v testRiskChangeView) DryRun() (bool, error)                               { return false, nil }
@@ -4,14 +4,17 @@
 package clusterapply
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
 
 	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	ctlresm "carvel.dev/kapp/pkg/kapp/resourcesmisc"
 	"carvel.dev/kapp/pkg/kapp/util"
 	uierrs "github.com/cppforlife/go-cli-ui/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 type WaitingChangesOpts struct {
@@ -19,8 +22,43 @@ type WaitingChangesOpts struct {
 	ResourceTimeout time.Duration
 	CheckInterval   time.Duration
 	Concurrency     int
+
+	// Watch enables checking resources as soon as they change
+	// instead of checking all of them every CheckInterval
+	Watch bool
+	// WatchFunc is used to start watching resources of given group kinds
+	WatchFunc func([]schema.GroupKind) (WaitingChangesWatch, error)
+}
+
+// WaitingChangesWatch reports resources (including associated resources,
+// like Pods of a Deployment) that changed while waiting.
+type WaitingChangesWatch interface {
+	Events() <-chan ctlres.Resource
+	IsWatched(schema.GroupKind) bool
+	Stop()
 }
 
+const (
+	// Even watched resources are periodically checked
+	// in case some events were missed (e.g. watch expired)
+	waitWatchResyncIntervals = 10
+	// Changes are typically reported via multiple events in quick succession
+	// (e.g. Deployment, its ReplicaSet and Pods), hence wait a bit before checking
+	waitWatchDebounce = 200 * time.Millisecond
+)
+
+var (
+	// Associated resources that are used to determine whether resources
+	// are done applying (see ConvergedResourceFactory) are watched as well,
+	// hence only resources that cannot be watched need to be polled
+	waitWatchAssociatedGKs = []schema.GroupKind{
+		{Group: "", Kind: "Pod"},
+		{Group: "", Kind: "PersistentVolumeClaim"},
+		{Group: "apps", Kind: "ReplicaSet"},
+		{Group: "batch", Kind: "Job"},
+	}
+)
+
 type WaitingChanges struct {
 	numTotal       int // for ui
 	numWaited      int // for ui
@@ -28,26 +66,82 @@ type WaitingChanges struct {
 	opts           WaitingChangesOpts
 	ui             UI
 	exitOnError    bool
+
+	watch        WaitingChangesWatch
+	watchStarted bool
+	// trackedKeys contains keys of tracked changes' resources
+	trackedKeys map[string]struct{}
+	// owners maps resource keys to keys of their owners
+	// so that changes to associated resources can be attributed
+	owners  map[string][]string
+	changed map[string]struct{}
 }
 
 type WaitingChange struct {
-	Graph     *ctldgraph.Change
-	Cluster   *ClusterChange
-	startTime time.Time
+	Graph         *ctldgraph.Change
+	Cluster       *ClusterChange
+	startTime     time.Time
+	lastCheckTime time.Time
 }
 
 func NewWaitingChanges(numTotal int, opts WaitingChangesOpts, ui UI, exitOnError bool) *WaitingChanges {
-	return &WaitingChanges{numTotal, 0, nil, opts, ui, exitOnError}
+	return &WaitingChanges{numTotal: numTotal, opts: opts, ui: ui, exitOnError: exitOnError,
+		trackedKeys: map[string]struct{}{}, owners: map[string][]string{}, changed: map[string]struct{}{}}
 }
 
 func (c *WaitingChanges) Track(changes []WaitingChange) {
 	c.trackedChanges = append(c.trackedChanges, changes...)
+
+	for _, change := range changes {
+		c.trackedKeys[waitWatchResKey(change.Cluster.Resource())] = struct{}{}
+	}
+}
+
+// untrack forgets about completed changes and owners
+// of associated resources that are no longer relevant
+func (c *WaitingChanges) untrack(changes []WaitingChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	for _, change := range changes {
+		delete(c.trackedKeys, waitWatchResKey(change.Cluster.Resource()))
+	}
+
+	for key := range c.owners {
+		if !c.ownedByTracked(key) {
+			delete(c.owners, key)
+		}
+	}
 }
 
 func (c *WaitingChanges) IsEmpty() bool {
 	return len(c.trackedChanges) == 0
 }
 
+// StartWatching starts watching resources of given group kinds (and their associated
+// resources). Resources that cannot be watched continue to be checked every CheckInterval.
+func (c *WaitingChanges) StartWatching(gks []schema.GroupKind) {
+	if !c.opts.Watch || c.opts.WatchFunc == nil || c.watch != nil || len(gks) == 0 {
+		return
+	}
+
+	watch, err := c.opts.WatchFunc(append(gks, waitWatchAssociatedGKs...))
+	if err != nil {
+		c.ui.Notify([]string{fmt.Sprintf("Falling back to polling since watching resources failed: %s", err)})
+		return
+	}
+
+	c.watch = watch
+}
+
+func (c *WaitingChanges) StopWatching() {
+	if c.watch != nil {
+		c.watch.Stop()
+		c.watch = nil
+	}
+}
+
 type waitResult struct {
 	Change   WaitingChange
 	State    ctlresm.DoneApplyState
@@ -55,23 +149,38 @@ type waitResult struct {
 	Err      error
 }
 
-func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
+func (c *WaitingChanges) WaitForAny(ctx context.Context) ([]WaitingChange, []string, error) {
 	startTime := time.Now()
 
 	for {
-		c.ui.NotifySection("waiting on %d changes %s", len(c.trackedChanges), c.stats())
+		if err := ctx.Err(); err != nil {
+			return nil, nil, fmt.Errorf("Waiting for changes: %w", err)
+		}
+
+		changesToCheck, uncheckedChanges := c.changesToCheck()
 
-		waitCh := make(chan waitResult, len(c.trackedChanges))
+		var newInProgressChanges []WaitingChange
+		var doneChanges []WaitingChange
+		var completedChanges []WaitingChange
+		var unsuccessfulChangeDesc []string
+
+		if len(changesToCheck) > 0 {
+			c.ui.NotifySection("waiting on %d changes %s", len(c.trackedChanges), c.stats())
+		}
+
+		waitCh := make(chan waitResult, len(changesToCheck))
 		waitThrottle := util.NewThrottle(c.opts.Concurrency)
 
-		for _, change := range c.trackedChanges {
+		for _, change := range changesToCheck {
 			change := change // copy
 
 			go func() {
 				waitThrottle.Take()
 				defer waitThrottle.Done()
 
-				state, descMsgs, err := change.Cluster.IsDoneApplying()
+				change.lastCheckTime = time.Now()
+
+				state, descMsgs, err := change.Cluster.IsDoneApplying(ctx)
 				// check for resource timeout
 				if err == nil {
 					if c.opts.ResourceTimeout != 0 && time.Now().Sub(change.startTime) > c.opts.ResourceTimeout {
@@ -82,11 +191,7 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
 			}()
 		}
 
-		var newInProgressChanges []WaitingChange
-		var doneChanges []WaitingChange
-		var unsuccessfulChangeDesc []string
-
-		for i := 0; i < len(c.trackedChanges); i++ {
+		for i := 0; i < len(changesToCheck); i++ {
 			result := <-waitCh
 			change, state, descMsgs, err := result.Change, result.State, result.DescMsgs, result.Err
 
@@ -98,11 +203,13 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
 				if c.exitOnError {
 					return nil, nil, err
 				}
+				completedChanges = append(completedChanges, change)
 				unsuccessfulChangeDesc = append(unsuccessfulChangeDesc, err.Error())
 				continue
 			}
 			if state.Done {
 				c.numWaited++
+				completedChanges = append(completedChanges, change)
 			}
 
 			switch {
@@ -129,7 +236,8 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
 			}
 		}
 
-		c.trackedChanges = newInProgressChanges
+		c.trackedChanges = append(newInProgressChanges, uncheckedChanges...)
+		c.untrack(completedChanges)
 
 		if len(c.trackedChanges) == 0 || len(doneChanges) > 0 || len(unsuccessfulChangeDesc) > 0 {
 			return doneChanges, unsuccessfulChangeDesc, nil
@@ -143,10 +251,143 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
 			return nil, unsuccessfulChangeDesc, uierrs.NewSemiStructuredError(fmt.Errorf("Timed out waiting after %s for resources: [%s]", c.opts.Timeout, strings.Join(trackedResourcesDesc, ", ")))
 		}
 
-		time.Sleep(c.opts.CheckInterval)
+		c.waitForNextCheck(ctx)
+	}
+}
+
+// changesToCheck returns changes that should be checked now. Without a watch
+// all changes are checked; otherwise only changes whose resources (or associated
+// resources) changed, or that cannot be watched, or that are due for a resync.
+func (c *WaitingChanges) changesToCheck() ([]WaitingChange, []WaitingChange) {
+	if c.watch == nil {
+		return c.trackedChanges, nil
+	}
+
+	var toCheck, unchecked []WaitingChange
+
+	now := time.Now()
+
+	for _, change := range c.trackedChanges {
+		res := change.Cluster.Resource()
+		sinceLastCheck := now.Sub(change.lastCheckTime)
+		_, changed := c.changed[waitWatchResKey(res)]
+
+		switch {
+		case change.lastCheckTime.IsZero() || changed:
+			toCheck = append(toCheck, change)
+		case !c.watch.IsWatched(res.GroupKind()) || len(res.Name()) == 0:
+			if sinceLastCheck >= c.opts.CheckInterval {
+				toCheck = append(toCheck, change)
+			} else {
+				unchecked = append(unchecked, change)
+			}
+		case sinceLastCheck >= waitWatchResyncIntervals*c.opts.CheckInterval:
+			toCheck = append(toCheck, change)
+		case c.opts.ResourceTimeout != 0 && now.Sub(change.startTime) > c.opts.ResourceTimeout:
+			toCheck = append(toCheck, change)
+		default:
+			unchecked = append(unchecked, change)
+		}
+	}
+
+	c.changed = map[string]struct{}{}
+
+	return toCheck, unchecked
+}
+
+// waitForNextCheck waits for CheckInterval to elapse or,
+// when watching, for a tracked resource to change.
+func (c *WaitingChanges) waitForNextCheck(ctx context.Context) {
+	if c.watch == nil {
+		select {
+		case <-time.After(c.opts.CheckInterval):
+		case <-ctx.Done():
+		}
+		return
+	}
+
+	checkCh := time.After(c.opts.CheckInterval)
+	debouncing := false
+
+	for {
+		select {
+		case res := <-c.watch.Events():
+			if c.markChanged(res) && !debouncing && waitWatchDebounce < c.opts.CheckInterval {
+				checkCh = time.After(waitWatchDebounce)
+				debouncing = true
+			}
+		case <-checkCh:
+			return
+		case <-ctx.Done():
+			return
+		}
 	}
 }
 
+// markChanged records that resource and its owners changed
+// and returns true if any of them are tracked changes.
+func (c *WaitingChanges) markChanged(res ctlres.Resource) bool {
+	key := waitWatchResKey(res)
+
+	var ownerKeys []string
+	for _, ref := range res.OwnerRefs() {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			continue
+		}
+		ownerKeys = append(ownerKeys, waitWatchKey(gv.WithKind(ref.Kind).GroupKind(), res.Namespace(), ref.Name))
+	}
+	if len(ownerKeys) > 0 {
+		c.owners[key] = ownerKeys
+	}
+
+	var tracked bool
+
+	keysToMark := []string{key}
+	for len(keysToMark) > 0 {
+		key, keysToMark = keysToMark[0], keysToMark[1:]
+		if _, found := c.changed[key]; found {
+			continue
+		}
+		c.changed[key] = struct{}{}
+		if _, found := c.trackedKeys[key]; found {
+			tracked = true
+		}
+		keysToMark = append(keysToMark, c.owners[key]...)
+	}
+
+	return tracked
+}
+
+// ownedByTracked returns true if resource or any of its
+// (transitive) owners are tracked changes
+func (c *WaitingChanges) ownedByTracked(key string) bool {
+	visited := map[string]struct{}{}
+	keysToCheck := []string{key}
+
+	for len(keysToCheck) > 0 {
+		key, keysToCheck = keysToCheck[0], keysToCheck[1:]
+		if _, found := visited[key]; found {
+			continue
+		}
+		visited[key] = struct{}{}
+		if _, found := c.trackedKeys[key]; found {
+			return true
+		}
+		keysToCheck = append(keysToCheck, c.owners[key]...)
+	}
+
+	return false
+}
+
+func waitWatchResKey(res ctlres.Resource) string {
+	return waitWatchKey(res.GroupKind(), res.Namespace(), res.Name())
+}
+
+func waitWatchKey(gk schema.GroupKind, ns, name string) string {
+	return gk.String() + "/" + ns + "/" + name
+}
+
 func (c *WaitingChanges) Complete() error {
 	c.ui.NotifySection("waiting complete %s", c.stats())
 	return nil
@@ -0,0 +1,178 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"testing"
+	"time"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestWaitingChangesMarkChangedPropagatesToOwners(t *testing.T) {
+	changes := NewWaitingChanges(1, WaitingChangesOpts{}, nil, false)
+	changes.Track([]WaitingChange{newTestWaitingChange(t, deploymentYAML)})
+
+	require.True(t, changes.markChanged(mustNewTestResource(t, replicaSetYAML)),
+		"Expected ReplicaSet change to be attributed to tracked Deployment")
+	require.Contains(t, changes.changed, "Deployment.apps/ns/app")
+
+	changes.changed = map[string]struct{}{}
+
+	// Owner of ReplicaSet is known from previous event
+	require.True(t, changes.markChanged(mustNewTestResource(t, podYAML)),
+		"Expected Pod change to be attributed to tracked Deployment via ReplicaSet")
+	require.Contains(t, changes.changed, "Pod/ns/app-abc-xyz")
+	require.Contains(t, changes.changed, "ReplicaSet.apps/ns/app-abc")
+	require.Contains(t, changes.changed, "Deployment.apps/ns/app")
+
+	require.False(t, changes.markChanged(mustNewTestResource(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other
+  namespace: ns
+`)), "Expected unrelated resource change to not be attributed to tracked changes")
+}
+
+func TestWaitingChangesUntrackPrunesOwners(t *testing.T) {
+	deployment := newTestWaitingChange(t, deploymentYAML)
+
+	changes := NewWaitingChanges(1, WaitingChangesOpts{}, nil, false)
+	changes.Track([]WaitingChange{deployment})
+
+	changes.markChanged(mustNewTestResource(t, replicaSetYAML))
+	changes.markChanged(mustNewTestResource(t, podYAML))
+	changes.markChanged(mustNewTestResource(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other
+  namespace: ns
+`))
+	require.Len(t, changes.owners, 2, "Expected only resources with owners to be recorded")
+
+	changes.untrack([]WaitingChange{deployment})
+	require.Empty(t, changes.trackedKeys)
+	require.Empty(t, changes.owners, "Expected owners of completed changes to be dropped")
+
+	require.False(t, changes.markChanged(mustNewTestResource(t, podYAML)),
+		"Expected Pod change to not be attributed to completed Deployment")
+}
+
+func TestWaitingChangesChangesToCheck(t *testing.T) {
+	opts := WaitingChangesOpts{CheckInterval: time.Second}
+	now := time.Now()
+
+	deployment := newTestWaitingChange(t, deploymentYAML)
+	pod := newTestWaitingChange(t, podYAML)
+
+	t.Run("without watch all changes are checked", func(t *testing.T) {
+		changes := NewWaitingChanges(2, opts, nil, false)
+		deployment.lastCheckTime = now
+		changes.Track([]WaitingChange{deployment, pod})
+
+		toCheck, unchecked := changes.changesToCheck()
+		require.Len(t, toCheck, 2)
+		require.Empty(t, unchecked)
+	})
+
+	t.Run("with watch", func(t *testing.T) {
+		testCases := []struct {
+			description   string
+			lastCheckTime time.Time
+			changed       bool
+			watched       bool
+			expectedCheck bool
+		}{
+			{"never checked", time.Time{}, false, true, true},
+			{"recently checked and unchanged", now, false, true, false},
+			{"recently checked and changed", now, true, true, true},
+			{"due for resync", now.Add(-waitWatchResyncIntervals * opts.CheckInterval), false, true, true},
+			{"not watched and recently checked", now, false, false, false},
+			{"not watched and checked before check interval", now.Add(-opts.CheckInterval), false, false, true},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.description, func(t *testing.T) {
+				changes := NewWaitingChanges(1, opts, nil, false)
+				changes.watch = testWaitingChangesWatch{watched: testCase.watched}
+
+				change := deployment
+				change.lastCheckTime = testCase.lastCheckTime
+				changes.Track([]WaitingChange{change})
+
+				if testCase.changed {
+					changes.markChanged(change.Cluster.Resource())
+				}
+
+				toCheck, unchecked := changes.changesToCheck()
+				if testCase.expectedCheck {
+					require.Len(t, toCheck, 1)
+					require.Empty(t, unchecked)
+				} else {
+					require.Empty(t, toCheck)
+					require.Len(t, unchecked, 1)
+				}
+				require.Empty(t, changes.changed, "Expected changed resources to be reset after check")
+			})
+		}
+	})
+}
+
+const (
+	deploymentYAML = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: ns
+`
+	replicaSetYAML = `
+apiVersion: apps/v1
+kind: ReplicaSet
+metadata:
+  name: app-abc
+  namespace: ns
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: Deployment
+    name: app
+    uid: deployment-uid
+`
+	podYAML = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: app-abc-xyz
+  namespace: ns
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: ReplicaSet
+    name: app-abc
+    uid: replicaset-uid
+`
+)
+
+type testWaitingChangesWatch struct {
+	watched bool
+}
+
+func (w testWaitingChangesWatch) Events() <-chan ctlres.Resource  { return nil }
+func (w testWaitingChangesWatch) IsWatched(schema.GroupKind) bool { return w.watched }
+func (w testWaitingChangesWatch) Stop()                           {}
+
+func newTestWaitingChange(t *testing.T, yaml string) WaitingChange {
+	res := mustNewTestResource(t, yaml)
+	return WaitingChange{Cluster: &ClusterChange{change: ctldiff.NewChange(nil, res, res, nil, ctldiff.ChangeOpts{})}}
+}
+
+func mustNewTestResource(t *testing.T, yaml string) ctlres.Resource {
+	res, err := ctlres.NewResourceFromBytes([]byte(yaml))
+	require.NoError(t, err)
+	return res
+}
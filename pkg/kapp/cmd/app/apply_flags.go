@@ -49,6 +49,10 @@ func (s *ApplyFlags) SetWithDefaults(prefix string, defaults ApplyFlags, cmd *co
 
 	cmd.Flags().StringVar(&s.AddOrUpdateChangeOpts.DefaultUpdateStrategy, prefix+"apply-default-update-strategy",
 		defaults.AddOrUpdateChangeOpts.DefaultUpdateStrategy, "Change default update strategy")
+	cmd.Flags().StringVar(&s.AddOrUpdateChangeOpts.ServerSideApplyFieldManager, prefix+"apply-server-side-field-manager",
+		"kapp", "Field manager used by server-side-apply update strategy")
+	cmd.Flags().BoolVar(&s.AddOrUpdateChangeOpts.ServerSideApplyForceConflicts, prefix+"apply-server-side-force-conflicts",
+		false, "Take ownership of fields owned by other field managers when using server-side-apply update strategy")
 
 	cmd.Flags().BoolVar(&s.ExitEarlyOnApplyError, prefix+"exit-early-on-apply-error", true, "Exit quickly on apply failure")
 
@@ -63,6 +67,10 @@ func (s *ApplyFlags) SetWithDefaults(prefix string, defaults ApplyFlags, cmd *co
 		mustParseDuration("3s"), "Amount of time to sleep between checks while waiting")
 	cmd.Flags().IntVar(&s.WaitingChangesOpts.Concurrency, prefix+"wait-concurrency",
 		5, "Maximum number of concurrent wait operations")
+	cmd.Flags().DurationVar(&s.DeleteChangeOpts.ForceFinalizeGracePeriod, prefix+"wait-force-finalize-grace-period",
+		mustParseDuration("5m"), "Amount of time to wait on finalizers before removing them from resources with 'force-finalize' delete strategy")
+	cmd.Flags().BoolVar(&s.WaitingChangesOpts.Watch, prefix+"wait-watch", true,
+		"Watch resources to check them as soon as they change (resources that cannot be watched are checked every wait check interval)")
 
 	cmd.Flags().BoolVar(&s.ExitStatus, prefix+"apply-exit-status", false, "Return specific exit status based on number of changes")
 
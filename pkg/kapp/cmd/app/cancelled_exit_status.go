@@ -0,0 +1,25 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+)
+
+// CancelledExitStatus indicates that applying changes
+// was interrupted (e.g. via Ctrl-C) before it completed
+type CancelledExitStatus struct {
+	Err error
+}
+
+var _ ExitStatus = CancelledExitStatus{}
+
+func (d CancelledExitStatus) Error() string {
+	return fmt.Sprintf("Cancelled applying changes: %s (exit status %d)", d.Err, d.ExitStatus())
+}
+
+func (d CancelledExitStatus) Unwrap() error { return d.Err }
+
+// ExitStatus follows shell convention for processes terminated by SIGINT
+func (d CancelledExitStatus) ExitStatus() int { return 130 }
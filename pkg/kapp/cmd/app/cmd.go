@@ -0,0 +1,20 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "app",
+		Short: "App",
+		Annotations: map[string]string{
+			cmdcore.AppSupportHelpGroup.Key: cmdcore.AppSupportHelpGroup.Value,
+		},
+	}
+	return cmd
+}
@@ -4,6 +4,9 @@
 package app
 
 import (
+	"context"
+	"fmt"
+
 	ctlapp "carvel.dev/kapp/pkg/kapp/app"
 	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
 	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
@@ -16,6 +19,7 @@ import (
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	"github.com/cppforlife/go-cli-ui/ui"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 type DeleteOptions struct {
@@ -29,6 +33,10 @@ type DeleteOptions struct {
 	ApplyFlags          ApplyFlags
 	ResourceTypesFlags  ResourceTypesFlags
 	PrevAppFlags        PrevAppFlags
+	LockFlags           LockFlags
+
+	DeletionProtectionFlags DeletionProtectionFlags
+	RiskFlags               RiskFlags
 }
 
 type changesSummary struct {
@@ -52,16 +60,23 @@ func NewDeleteCmd(o *DeleteOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 	}
 	o.AppFlags.Set(cmd, flagsFactory)
 	o.DiffFlags.SetWithPrefix("diff", cmd)
+	o.DiffFlags.SetReportWithPrefix("diff", cmd)
 	o.ResourceFilterFlags.Set(cmd)
 	o.ApplyFlags.SetWithDefaults("", ApplyFlagsDeleteDefaults, cmd)
 	o.ResourceTypesFlags.Set(cmd)
 	o.PrevAppFlags.Set(cmd)
+	o.LockFlags.Set(cmd)
+	o.DeletionProtectionFlags.Set(cmd)
+	o.RiskFlags.Set(cmd)
 	return cmd
 }
 
 func (o *DeleteOptions) Run() error {
 	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
 
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
 	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
 	if err != nil {
 		return err
@@ -97,6 +112,18 @@ func (o *DeleteOptions) Run() error {
 		}
 	}
 
+	if !o.DiffFlags.Run {
+		lock, err := app.Lock(ctlapp.LockOpts{Description: "kapp delete", Force: o.LockFlags.ForceUnlock})
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if unlockErr := lock.Unlock(); unlockErr != nil {
+				o.ui.ErrorLinef("Warning: Releasing app lock: %s", unlockErr)
+			}
+		}()
+	}
+
 	usedGVs, err := app.UsedGVs()
 	if err != nil {
 		return err
@@ -109,20 +136,76 @@ func (o *DeleteOptions) Run() error {
 		return err
 	}
 
+	// Delete hooks and deletion protection rules are only known
+	// from resources recorded during last deploy
+	recorded, recordedFound, err := lastRecordedResources(app)
+	if err != nil {
+		return err
+	}
+	if !recordedFound {
+		// Common for apps deployed before resources were recorded,
+		// which do not have delete hooks, hence not worth a warning
+		o.logger.Debug("resources recorded during last deploy of %s were not found", app.Description())
+	}
+
+	recordedResources, recordedConf, err := ctlconf.NewConfFromResourcesWithDefaults(recorded.Resources)
+	if err != nil {
+		return err
+	}
+
+	var hookResources []ctlres.Resource
+
+	// Delete hooks are only run when app is fully deleted
+	if shouldFullyDeleteApp {
+		hookResources, err = o.hookResources(app, recordedResources, recorded.Opts, recordedConf, supportObjs)
+		if err != nil {
+			return err
+		}
+	}
+
 	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(nil)
 	if err != nil {
 		return err
 	}
 
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return err
+	}
+
+	meta, err := app.Meta()
+	if err != nil {
+		return err
+	}
+
+	// Only requests made while applying changes are bound to cancellable context
+	// so that other requests (e.g. deleting hook resources) are not affected by it
+	applySupportObjs := supportObjs
+	applySupportObjs.IdentifiedResources = supportObjs.IdentifiedResources.WithContext(ctx)
+
+	o.ApplyFlags.WaitingChangesOpts.WatchFunc = func(gks []schema.GroupKind) (ctlcap.WaitingChangesWatch, error) {
+		return applySupportObjs.IdentifiedResources.Watch(labelSelector, gks, meta.LastChange.Namespaces)
+	}
+
 	clusterChangeSet, clusterChangesGraph, changesSummary, err :=
-		o.calculateAndPresentChanges(existingResources, conf, supportObjs)
+		o.calculateAndPresentChanges(existingResources, hookResources, conf, applySupportObjs)
 	if err != nil {
 		if o.DiffFlags.UI && clusterChangesGraph != nil {
-			return o.presentDiffUI(clusterChangesGraph)
+			return o.presentDiffUI(app, clusterChangesGraph, conf)
 		}
 		return err
 	}
 
+	err = o.writeDiffReport(app, clusterChangesGraph, conf)
+	if err != nil {
+		return err
+	}
+
+	err = o.RiskFlags.RiskCheck(conf).Check(clusterChangesGraph)
+	if err != nil {
+		return err
+	}
+
 	if changesSummary.SkippedChanges {
 		shouldFullyDeleteApp = false
 	}
@@ -133,8 +216,8 @@ func (o *DeleteOptions) Run() error {
 			app.Name(), o.AppFlags.NamespaceFlags.Name)
 	}
 
-	if o.DiffFlags.UI {
-		return o.presentDiffUI(clusterChangesGraph)
+	if o.DiffFlags.UI && o.DiffFlags.Run {
+		return o.presentDiffUI(app, clusterChangesGraph, conf)
 	}
 
 	if o.DiffFlags.Run {
@@ -144,11 +227,24 @@ func (o *DeleteOptions) Run() error {
 		return nil
 	}
 
-	err = o.ui.AskForConfirmation()
+	err = o.DeletionProtectionFlags.DeletionProtection(recordedConf).Check(clusterChangesGraph)
+	if err != nil {
+		return err
+	}
+
+	if o.DiffFlags.UI && o.ui.IsInteractive() {
+		err = o.approveInDiffUI(app, clusterChangesGraph, conf)
+	} else {
+		err = o.ui.AskForConfirmation()
+	}
 	if err != nil {
 		return err
 	}
 
+	// Only watch for signals after confirmation to keep default behaviour when prompting
+	stopWatchingFunc := cmdcore.CancelSignals{}.Watch(cancelFunc)
+	defer stopWatchingFunc()
+
 	if !shouldFullyDeleteApp {
 		defer func() {
 			_, numDeleted, _ := app.GCChanges(ctlapp.AppChangesMaxToKeepDefault, nil)
@@ -158,11 +254,14 @@ func (o *DeleteOptions) Run() error {
 		}()
 	}
 
-	touch := ctlapp.Touch{App: app, Description: "delete", IgnoreSuccessErr: true}
+	touch := ctlapp.Touch{App: app, Description: deleteChangeDescription, IgnoreSuccessErr: true}
 
 	err = touch.Do(func() error {
-		err := clusterChangeSet.Apply(clusterChangesGraph)
+		err := clusterChangeSet.Apply(ctx, clusterChangesGraph)
 		if err != nil {
+			if ctx.Err() != nil {
+				return ctlapp.ChangeFailedError{Err: CancelledExitStatus{err}, Reason: "cancelled"}
+			}
 			if shouldFullyDeleteApp {
 				_, numDeleted, _ := app.GCChanges(5, nil)
 				if numDeleted > 0 {
@@ -172,6 +271,11 @@ func (o *DeleteOptions) Run() error {
 			return err
 		}
 		if shouldFullyDeleteApp {
+			// Hooks are deleted since they would not be owned by any app
+			err := o.deleteHookResources(hookResources, supportObjs)
+			if err != nil {
+				return err
+			}
 			return app.Delete()
 		}
 		return nil
@@ -224,7 +328,7 @@ func (o *DeleteOptions) existingResources(app ctlapp.App,
 	return existingResources, fullyDeleteApp, nil
 }
 
-func (o *DeleteOptions) calculateAndPresentChanges(existingResources []ctlres.Resource, conf ctlconf.Conf,
+func (o *DeleteOptions) calculateAndPresentChanges(existingResources, hookResources []ctlres.Resource, conf ctlconf.Conf,
 	supportObjs FactorySupportObjs) (ctlcap.ClusterChangeSet, *ctldgraph.ChangeGraph, changesSummary, error) {
 
 	var (
@@ -232,16 +336,16 @@ func (o *DeleteOptions) calculateAndPresentChanges(existingResources []ctlres.Re
 		skippedChanges   bool
 	)
 
-	{ // Figure out changes for X existing resources -> 0 new resources
+	{ // Figure out changes for X existing resources -> 0 new resources (except delete hooks)
 		changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{o.DiffFlags.AnchoredDiff})
 		changeSetFactory := ctldiff.NewChangeSetFactory(o.DiffFlags.ChangeSetOpts, changeFactory)
 
-		changes, err := changeSetFactory.New(existingResources, nil).Calculate()
+		diffFilter, err := o.DiffFlags.DiffFilter()
 		if err != nil {
 			return ctlcap.ClusterChangeSet{}, nil, changesSummary{}, err
 		}
 
-		diffFilter, err := o.DiffFlags.DiffFilter()
+		changes, err := changeSetFactory.New(existingResources, hookResources).Calculate()
 		if err != nil {
 			return ctlcap.ClusterChangeSet{}, nil, changesSummary{}, err
 		}
@@ -251,6 +355,15 @@ func (o *DeleteOptions) calculateAndPresentChanges(existingResources []ctlres.Re
 		if len(changes) != len(appliedChanges) {
 			// setting it to true when changes are filtered by diffFilter
 			skippedChanges = true
+
+			// Delete hooks are only run when app is fully deleted
+			if len(hookResources) > 0 {
+				changes, err = changeSetFactory.New(existingResources, nil).Calculate()
+				if err != nil {
+					return ctlcap.ClusterChangeSet{}, nil, changesSummary{}, err
+				}
+				appliedChanges = diffFilter.Apply(changes)
+			}
 		}
 
 		{ // Build cluster changes based on diff changes
@@ -278,15 +391,67 @@ func (o *DeleteOptions) calculateAndPresentChanges(existingResources []ctlres.Re
 	{ // Present cluster changes in UI
 		changeViews := ctlcap.ClusterChangesAsChangeViews(clusterChanges)
 		changeSetView := ctlcap.NewChangeSetView(
-			changeViews, conf.DiffMaskRules(), o.DiffFlags.ChangeSetViewOpts)
-		changeSetView.Print(o.ui)
+			changeViews, conf.DiffMaskRules(), conf.DiffMergeKeyRules(), conf.RiskRules(), o.DiffFlags.ChangeSetViewOpts)
+		err = changeSetView.Print(o.ui)
+		if err != nil {
+			return ctlcap.ClusterChangeSet{}, nil, changesSummary{}, err
+		}
 	}
 
 	return clusterChangeSet, clusterChangesGraph, changesSummary{HasNoChanges: len(clusterChanges) == 0, SkippedChanges: skippedChanges}, nil
 }
 
+// hookResources returns delete hooks recorded during last deploy
+func (o *DeleteOptions) hookResources(app ctlapp.App, resources []ctlres.Resource, opts ctlapp.RecordedResourcesOpts,
+	conf ctlconf.Conf, supportObjs FactorySupportObjs) ([]ctlres.Resource, error) {
+
+	_, hookResources, err := ctlres.SplitDeleteHooks(resources)
+	if err != nil || len(hookResources) == 0 {
+		return nil, err
+	}
+
+	prep := ctlapp.NewPreparation(supportObjs.ResourceTypes, ctlapp.PrepareResourcesOpts{
+		BeforeModificationFunc: func(rs []ctlres.Resource) []ctlres.Resource { return rs },
+		IntoNamespace:          opts.IntoNamespace,
+		MapNamespaces:          opts.MapNamespaces,
+		DefaultNamespace:       o.AppFlags.NamespaceFlags.Name,
+	})
+
+	hookResources, err = prep.PrepareResources(hookResources)
+	if err != nil {
+		return nil, err
+	}
+
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return nil, err
+	}
+
+	err = ctlres.NewLabeledResources(labelSelector, supportObjs.IdentifiedResources, o.logger).Prepare(
+		hookResources, conf.OwnershipLabelMods(), conf.LabelScopingMods(true), conf.AdditionalLabels())
+	if err != nil {
+		return nil, err
+	}
+
+	return hookResources, nil
+}
+
+// deleteHookResources deletes hooks left after they have run
+// (hooks deleted after succeeding are already gone)
+func (o *DeleteOptions) deleteHookResources(hookResources []ctlres.Resource, supportObjs FactorySupportObjs) error {
+	for _, res := range hookResources {
+		err := supportObjs.IdentifiedResources.Delete(res)
+		if err != nil {
+			return fmt.Errorf("Deleting hook %s: %w", res.Description(), err)
+		}
+	}
+	return nil
+}
+
 const (
 	ownedForDeletionAnnKey = "kapp.k14s.io/owned-for-deletion" // valid values: ''
+
+	deleteChangeDescription = "delete"
 )
 
 func (o *DeleteOptions) changeIgnored(resources []ctlres.Resource) {
@@ -300,9 +465,50 @@ func (o *DeleteOptions) changeIgnored(resources []ctlres.Resource) {
 	}
 }
 
-func (o *DeleteOptions) presentDiffUI(graph *ctldgraph.ChangeGraph) error {
+func (o *DeleteOptions) presentDiffUI(app ctlapp.App, graph *ctldgraph.ChangeGraph, conf ctlconf.Conf) error {
+	reportOpts, err := o.diffReportOpts(app, graph, conf)
+	if err != nil {
+		return err
+	}
+
 	opts := ctldiffui.ServerOpts{
-		DiffDataFunc: func() *ctldgraph.ChangeGraph { return graph },
+		ReportOptsFunc: func() ctldiffui.ReportOpts { return reportOpts },
 	}
 	return ctldiffui.NewServer(opts, o.ui).Run()
 }
+
+func (o *DeleteOptions) approveInDiffUI(app ctlapp.App, graph *ctldgraph.ChangeGraph, conf ctlconf.Conf) error {
+	reportOpts, err := o.diffReportOpts(app, graph, conf)
+	if err != nil {
+		return err
+	}
+
+	opts := ctldiffui.ServerOpts{
+		ReportOptsFunc: func() ctldiffui.ReportOpts { return reportOpts },
+	}
+	return ctldiffui.NewServer(opts, o.ui).RunForApproval()
+}
+
+func (o *DeleteOptions) writeDiffReport(app ctlapp.App, graph *ctldgraph.ChangeGraph, conf ctlconf.Conf) error {
+	if len(o.DiffFlags.ReportHTML) == 0 {
+		return nil
+	}
+
+	reportOpts, err := o.diffReportOpts(app, graph, conf)
+	if err != nil {
+		return err
+	}
+
+	err = ctldiffui.NewReport(reportOpts).WriteToFile(o.DiffFlags.ReportHTML)
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Wrote diff report to '%s'", o.DiffFlags.ReportHTML)
+	return nil
+}
+
+func (o *DeleteOptions) diffReportOpts(app ctlapp.App, graph *ctldgraph.ChangeGraph, conf ctlconf.Conf) (ctldiffui.ReportOpts, error) {
+	title := fmt.Sprintf("kapp delete: app '%s' (namespace: %s)", app.Name(), o.AppFlags.NamespaceFlags.Name)
+	return ctlcap.NewChangeSetReportOpts(title, graph, conf.DiffMaskRules(), o.DiffFlags.ChangeSetViewOpts)
+}
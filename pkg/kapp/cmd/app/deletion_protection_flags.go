@@ -0,0 +1,29 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/spf13/cobra"
+)
+
+type DeletionProtectionFlags struct {
+	AllowedResources []string
+}
+
+func (s *DeletionProtectionFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&s.AllowedResources, "dangerous-allow-deleting-protected-resource", nil,
+		"Allow deletion of protected resource (format: kind.group/namespace/name or kind.group/name for cluster scoped resources, "+
+			"group is omitted for core resources, e.g. ConfigMap/ns/name or Deployment.apps/ns/name; can be specified multiple times)")
+}
+
+func (s *DeletionProtectionFlags) DeletionProtection(conf ctlconf.Conf) ctlcap.DeletionProtection {
+	var matchers []ctlres.ResourceMatcher
+	for _, rule := range conf.DeletionProtectionRules() {
+		matchers = append(matchers, rule.ResourceMatcher())
+	}
+	return ctlcap.DeletionProtection{ResourceMatchers: matchers, AllowedResources: s.AllowedResources}
+}
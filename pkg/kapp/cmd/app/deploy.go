@@ -10,6 +10,7 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/cppforlife/go-cli-ui/ui"
 	"github.com/spf13/cobra"
@@ -51,10 +52,20 @@ type DeployOptions struct {
 	DeployFlags         DeployFlags
 	ResourceTypesFlags  ResourceTypesFlags
 	LabelFlags          LabelFlags
+	LockFlags           LockFlags
+
+	DeletionProtectionFlags DeletionProtectionFlags
+	RiskFlags               RiskFlags
+	MultiClusterFlags       MultiClusterFlags
 
 	PreflightChecks *preflight.Registry
 
 	FileSystem fs.FS
+
+	// ResourcesFunc provides resources to deploy instead of reading them from files
+	ResourcesFunc func() ([]ctlres.Resource, error)
+
+	recordedResourceFilter *ctlres.ResourceFilter
 }
 
 func NewDeployOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger, preflights *preflight.Registry) *DeployOptions {
@@ -81,7 +92,10 @@ func NewDeployCmd(o *DeployOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
   # Deploy app 'app1' based on remote file
   kapp deploy -a app1 \
     -f https://github.com/...download/v0.6.0/crds.yaml \
-    -f https://github.com/...download/v0.6.0/release.yaml`,
+    -f https://github.com/...download/v0.6.0/release.yaml
+
+  # Deploy app 'app1' to several clusters after a single confirmation
+  kapp deploy -a app1 -f config/ --kubeconfig-context us-east,eu-west`,
 	}
 
 	setDeployCmdFlags(cmd)
@@ -89,11 +103,16 @@ func NewDeployCmd(o *DeployOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 	o.AppFlags.Set(cmd, flagsFactory)
 	o.FileFlags.Set(cmd)
 	o.DiffFlags.SetWithPrefix("diff", cmd)
+	o.DiffFlags.SetReportWithPrefix("diff", cmd)
 	o.ResourceFilterFlags.Set(cmd)
 	o.ApplyFlags.SetWithDefaults("", ApplyFlagsDeployDefaults, cmd)
 	o.DeployFlags.Set(cmd)
 	o.ResourceTypesFlags.Set(cmd)
 	o.LabelFlags.Set(cmd)
+	o.LockFlags.Set(cmd)
+	o.DeletionProtectionFlags.Set(cmd)
+	o.RiskFlags.Set(cmd)
+	o.MultiClusterFlags.Set(cmd)
 	o.PrevAppFlags.Set(cmd)
 	o.PreflightChecks.AddFlags(cmd.Flags())
 
@@ -101,8 +120,25 @@ func NewDeployCmd(o *DeployOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 }
 
 func (o *DeployOptions) Run() error {
+	targets, err := o.MultiClusterFlags.Targets(o.depsFactory)
+	if err != nil {
+		return err
+	}
+
+	if len(targets) > 0 {
+		return o.runMultiCluster(targets)
+	}
+
 	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
 
+	plan, err := o.prepareDeployPlan()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
 	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
 	if err != nil {
 		return err
@@ -113,12 +149,27 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
-	isNewApp, err := app.CreateOrUpdate(o.PrevAppFlags.PrevAppName, appLabels, o.DiffFlags.Run)
+	isNewApp, err := app.CreateOrUpdate(o.PrevAppFlags.PrevAppName, appLabels,
+		o.DiffFlags.Run || len(o.DeployFlags.PlanOutput) > 0)
 
 	if err != nil {
 		return err
 	}
 
+	// Lock before calculating changes so that they are not
+	// based on cluster state that's being changed concurrently
+	if !o.DiffFlags.Run && len(o.DeployFlags.PlanOutput) == 0 {
+		lock, err := app.Lock(ctlapp.LockOpts{Description: "kapp deploy", Force: o.LockFlags.ForceUnlock})
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if unlockErr := lock.Unlock(); unlockErr != nil {
+				o.ui.ErrorLinef("Warning: Releasing app lock: %s", unlockErr)
+			}
+		}()
+	}
+
 	usedGVs, err := app.UsedGVs()
 	if err != nil {
 		return err
@@ -142,12 +193,19 @@ func (o *DeployOptions) Run() error {
 
 	labeledResources := ctlres.NewLabeledResources(labelSelector, supportObjs.IdentifiedResources, o.logger)
 
-	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter()
+	resourceFilter, err := o.resourceFilter()
+	if err != nil {
+		return err
+	}
+
+	inputResources, err := o.inputResources()
 	if err != nil {
 		return err
 	}
 
-	newResources, conf, nsNames, newGKs, err := o.newResources(prep, labeledResources, resourceFilter)
+	recordedResources := o.recordedResources(inputResources, resourceFilter)
+
+	newResources, conf, nsNames, newGKs, err := o.newResources(inputResources, prep, labeledResources, resourceFilter)
 	if err != nil {
 		return err
 	}
@@ -163,28 +221,72 @@ func (o *DeployOptions) Run() error {
 	}
 
 	existingResources, existingPodRs, err := o.existingResources(
-		newResources, labeledResources, resourceFilter, supportObjs.Apps, usedGKs, append(meta.LastChange.Namespaces, nsNames...), isNewApp)
+		newResources, labeledResources, resourceFilter, conf, supportObjs.Apps, usedGKs, append(meta.LastChange.Namespaces, nsNames...), isNewApp)
 	if err != nil {
 		return err
 	}
 
+	// Only requests made while applying changes are bound to cancellable context
+	// so that other requests (e.g. listing resources) are not affected by it
+	applySupportObjs := supportObjs
+	applySupportObjs.IdentifiedResources = supportObjs.IdentifiedResources.WithContext(ctx)
+
+	o.ApplyFlags.WaitingChangesOpts.WatchFunc = func(gks []schema.GroupKind) (ctlcap.WaitingChangesWatch, error) {
+		return applySupportObjs.IdentifiedResources.Watch(labelSelector, gks, append(meta.LastChange.Namespaces, nsNames...))
+	}
+
 	clusterChangeSet, clusterChangesGraph, hasNoChanges, changeSummary, err :=
-		o.calculateAndPresentChanges(existingResources, newResources, conf, supportObjs)
+		o.calculateAndPresentChanges(existingResources, newResources, conf, applySupportObjs)
 	if err != nil {
 		if o.DiffFlags.UI && clusterChangesGraph != nil {
-			return o.presentDiffUI(clusterChangesGraph)
+			return o.presentDiffUI(app, clusterChangesGraph, conf)
 		}
 		return err
 	}
 
+	err = o.writeDiffReport(app, clusterChangesGraph, conf)
+	if err != nil {
+		return err
+	}
+
+	err = o.checkDryRunErrs(clusterChangesGraph)
+	if err != nil {
+		return err
+	}
+
+	err = o.RiskFlags.RiskCheck(conf).Check(clusterChangesGraph)
+	if err != nil {
+		return err
+	}
+
 	// Validate new resources _after_ presenting changes to make it easier to see big picture
 	err = prep.ValidateResources(newResources)
 	if err != nil {
 		return err
 	}
 
-	if o.DiffFlags.UI {
-		return o.presentDiffUI(clusterChangesGraph)
+	if len(o.DeployFlags.PlanOutput) > 0 {
+		newPlan, err := NewDeployPlan(app.Name(), o.DeployFlags.Nonce, recordedResources.Resources, clusterChangesGraph)
+		if err != nil {
+			return err
+		}
+		err = newPlan.WriteToFile(o.DeployFlags.PlanOutput)
+		if err != nil {
+			return err
+		}
+		o.ui.PrintLinef("Wrote deploy plan to '%s'", o.DeployFlags.PlanOutput)
+		return nil
+	}
+
+	if plan != nil {
+		err = plan.CheckDrift(clusterChangesGraph)
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.DiffFlags.UI && (o.DiffFlags.Run || hasNoChanges) {
+		return o.presentDiffUI(app, clusterChangesGraph, conf)
 	}
 
 	if o.DiffFlags.Run || hasNoChanges {
@@ -199,22 +301,36 @@ func (o *DeployOptions) Run() error {
 		return nil
 	}
 
+	err = o.DeletionProtectionFlags.DeletionProtection(conf).Check(clusterChangesGraph)
+	if err != nil {
+		return err
+	}
+
 	if o.PreflightChecks != nil {
 		err = o.PreflightChecks.SetConfig(conf.PreflightRules())
 		if err != nil {
 			return fmt.Errorf("preflight configuration settings failed: %w", err)
 		}
-		err = o.PreflightChecks.Run(context.Background(), clusterChangesGraph)
+		err = o.PreflightChecks.Run(ctx, clusterChangesGraph)
 		if err != nil {
 			return fmt.Errorf("preflight checks failed: %w", err)
 		}
 	}
 
-	err = o.ui.AskForConfirmation()
+	// Changes do not need to be approved in diff UI when prompts are skipped (e.g. via --yes)
+	if o.DiffFlags.UI && o.ui.IsInteractive() {
+		err = o.approveInDiffUI(app, clusterChangesGraph, conf)
+	} else {
+		err = o.ui.AskForConfirmation()
+	}
 	if err != nil {
 		return err
 	}
 
+	// Only watch for signals after confirmation to keep default behaviour when prompting
+	stopWatchingFunc := cmdcore.CancelSignals{}.Watch(cancelFunc)
+	defer stopWatchingFunc()
+
 	// Track newly added GVs and GKs
 	err = app.UpdateUsedGVsAndGKs(failingAPIServicesPolicy.GVs(newResources, existingResources),
 		NewUsedGKsScope(append(newResources, existingResources...)).GKs())
@@ -225,7 +341,7 @@ func (o *DeployOptions) Run() error {
 	if o.DeployFlags.Logs {
 		cancelLogsCh := make(chan struct{})
 		defer func() { close(cancelLogsCh) }()
-		go o.showLogs(supportObjs.CoreClient, supportObjs.IdentifiedResources, existingPodRs, labelSelector, cancelLogsCh, append(meta.LastChange.Namespaces, nsNames...))
+		go o.showLogs(ctx, supportObjs.CoreClient, supportObjs.IdentifiedResources, existingPodRs, labelSelector, cancelLogsCh, append(meta.LastChange.Namespaces, nsNames...))
 	}
 
 	defer func() {
@@ -239,15 +355,23 @@ func (o *DeployOptions) Run() error {
 		App:                 app,
 		Description:         "update: " + changeSummary,
 		Namespaces:          nsNames,
+		Resources:           recordedResources,
 		IgnoreSuccessErr:    true,
 		AppChangesMaxToKeep: o.DeployFlags.AppChangesMaxToKeep,
+		WarningFunc:         func(err error) { o.ui.ErrorLinef("Warning: %s (rollback to this change will not be possible)", err) },
 	}
 
 	err = touch.Do(func() error {
 		defer o.writeAppMetadataToFile(app)
 
-		err := clusterChangeSet.Apply(clusterChangesGraph)
+		err := clusterChangeSet.Apply(ctx, clusterChangesGraph)
 		if err != nil {
+			if ctx.Err() != nil {
+				return ctlapp.ChangeFailedError{Err: CancelledExitStatus{err}, Reason: "cancelled"}
+			}
+			if o.DeployFlags.RollbackOnFailure {
+				return o.rollback(ctx, err, app, prep, labeledResources, resourceFilter, conf, applySupportObjs, nsNames)
+			}
 			return err
 		}
 
@@ -265,6 +389,39 @@ func (o *DeployOptions) Run() error {
 	return nil
 }
 
+// prepareDeployPlan loads deploy plan, if one is specified, and ensures that
+// resources are prepared in the same way when plan is calculated and applied
+func (o *DeployOptions) prepareDeployPlan() (*DeployPlan, error) {
+	if len(o.DeployFlags.FromPlan) == 0 {
+		if len(o.DeployFlags.PlanOutput) > 0 {
+			o.DeployFlags.Nonce = fmt.Sprintf("%d", time.Now().UTC().UnixNano())
+		}
+		return nil, nil
+	}
+
+	if len(o.DeployFlags.PlanOutput) > 0 {
+		return nil, fmt.Errorf("Expected only one of --plan-output or --from-plan to be specified")
+	}
+	if len(o.FileFlags.Files) > 0 || o.ResourcesFunc != nil {
+		return nil, fmt.Errorf("Expected no --file (-f) to be specified when deploying from plan")
+	}
+
+	plan, err := NewDeployPlanFromFile(o.DeployFlags.FromPlan)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.App != o.AppFlags.Name {
+		return nil, fmt.Errorf("Expected deploy plan to be calculated for app '%s' but was for app '%s'",
+			o.AppFlags.Name, plan.App)
+	}
+
+	o.DeployFlags.Nonce = plan.Nonce
+	o.ResourcesFunc = func() ([]ctlres.Resource, error) { return plan.InputResources(), nil }
+
+	return &plan, nil
+}
+
 func (o *DeployOptions) newAndUsedGKs(newGKs []schema.GroupKind, app ctlapp.App) ([]schema.GroupKind, error) {
 	if o.DeployFlags.DisableGKScoping {
 		return []schema.GroupKind{}, nil
@@ -310,16 +467,17 @@ func (o *DeployOptions) newAndUsedGKs(newGKs []schema.GroupKind, app ctlapp.App)
 	return uniqGKs, nil
 }
 
-func (o *DeployOptions) newResources(
+func (o *DeployOptions) newResources(newResources []ctlres.Resource,
 	prep ctlapp.Preparation, labeledResources *ctlres.LabeledResources,
 	resourceFilter ctlres.ResourceFilter) ([]ctlres.Resource, ctlconf.Conf, []string, []schema.GroupKind, error) {
 
-	newResources, err := o.newResourcesFromFiles()
+	newResources, conf, err := ctlconf.NewConfFromResourcesWithDefaults(newResources)
 	if err != nil {
 		return nil, ctlconf.Conf{}, nil, nil, err
 	}
 
-	newResources, conf, err := ctlconf.NewConfFromResourcesWithDefaults(newResources)
+	// Delete hooks are applied only when app is deleted
+	newResources, _, err = ctlres.SplitDeleteHooks(newResources)
 	if err != nil {
 		return nil, ctlconf.Conf{}, nil, nil, err
 	}
@@ -338,11 +496,18 @@ func (o *DeployOptions) newResources(
 	newGKs := NewUsedGKsScope(newResources).GKs()
 
 	// Grab ns names before resource filtering is applied
-	nsNames := o.nsNames(newResources)
+	nsNames := resourceNsNames(newResources)
 
 	return resourceFilter.Apply(newResources), conf, nsNames, newGKs, nil
 }
 
+func (o *DeployOptions) inputResources() ([]ctlres.Resource, error) {
+	if o.ResourcesFunc != nil {
+		return o.ResourcesFunc()
+	}
+	return o.newResourcesFromFiles()
+}
+
 func (o *DeployOptions) newResourcesFromFiles() ([]ctlres.Resource, error) {
 	var allResources []ctlres.Resource
 
@@ -368,7 +533,7 @@ func (o *DeployOptions) newResourcesFromFiles() ([]ctlres.Resource, error) {
 }
 
 func (o *DeployOptions) existingResources(newResources []ctlres.Resource,
-	labeledResources *ctlres.LabeledResources, resourceFilter ctlres.ResourceFilter,
+	labeledResources *ctlres.LabeledResources, resourceFilter ctlres.ResourceFilter, conf ctlconf.Conf,
 	apps ctlapp.Apps, usedGKs []schema.GroupKind, resourceNamespaces []string, isNewApp bool) ([]ctlres.Resource, []ctlres.Resource, error) {
 
 	labelErrorResolutionFunc := func(key string, val string) string {
@@ -397,6 +562,7 @@ func (o *DeployOptions) existingResources(newResources []ctlres.Resource,
 		ExistingNonLabeledResourcesCheckConcurrency: o.DeployFlags.ExistingNonLabeledResourcesCheckConcurrency,
 		SkipResourceOwnershipCheck:                  o.DeployFlags.OverrideOwnershipOfExistingResources,
 		SkipOwnershipCheckAllowedApps:               o.DeployFlags.OwnershipOverrideAllowedApps,
+		AdoptionRules:                               conf.AdoptionRules(),
 		IsNewApp:                                    isNewApp,
 
 		// Prevent accidently overriding kapp state records
@@ -460,6 +626,19 @@ func (o *DeployOptions) calculateAndPresentChanges(existingResources,
 
 		changes = diffFilter.Apply(changes)
 
+		if o.DeployFlags.ServerSideDryRun {
+			dryRunOpts := ctlcap.DryRunChangesOpts{
+				Concurrency:           o.ApplyFlags.ApplyingChangesOpts.Concurrency,
+				AddOrUpdateChangeOpts: o.ApplyFlags.AddOrUpdateChangeOpts,
+			}
+
+			changes, err = ctlcap.NewDryRunChanges(dryRunOpts,
+				supportObjs.IdentifiedResources, changeFactory).Apply(changes)
+			if err != nil {
+				return clusterChangeSet, nil, false, "", err
+			}
+		}
+
 		msgsUI := cmdcore.NewDedupingMessagesUI(cmdcore.NewPlainMessagesUI(o.ui))
 
 		convergedResFactory := ctlcap.NewConvergedResourceFactory(conf.WaitRules(), ctlcap.ConvergedResourceFactoryOpts{
@@ -486,14 +665,34 @@ func (o *DeployOptions) calculateAndPresentChanges(existingResources,
 	{ // Present cluster changes in UI
 		changeViews := ctlcap.ClusterChangesAsChangeViews(clusterChanges)
 		changeSetView := ctlcap.NewChangeSetView(
-			changeViews, conf.DiffMaskRules(), o.DiffFlags.ChangeSetViewOpts)
-		changeSetView.Print(o.ui)
+			changeViews, conf.DiffMaskRules(), conf.DiffMergeKeyRules(), conf.RiskRules(), o.DiffFlags.ChangeSetViewOpts)
+		err = changeSetView.Print(o.ui)
+		if err != nil {
+			return clusterChangeSet, clusterChangesGraph, false, "", err
+		}
 		changesSummary = changeSetView.Summary()
 	}
 
 	return clusterChangeSet, clusterChangesGraph, (len(clusterChanges) == 0), changesSummary, err
 }
 
+func (o *DeployOptions) checkDryRunErrs(graph *ctldgraph.ChangeGraph) error {
+	var numFailed int
+	for _, change := range graph.All() {
+		view, ok := change.Change.(ctlcap.ChangeView)
+		if !ok {
+			return fmt.Errorf("Expected change '%s' to be cluster change", change.Change.Resource().Description())
+		}
+		if _, err := view.DryRun(); err != nil {
+			numFailed++
+		}
+	}
+	if numFailed > 0 {
+		return fmt.Errorf("Server-side dry run failed for %d changes", numFailed)
+	}
+	return nil
+}
+
 func (o *DeployOptions) existingPodResources(existingResources []ctlres.Resource) []ctlres.Resource {
 	var existingPods []ctlres.Resource
 	for _, res := range existingResources {
@@ -525,7 +724,7 @@ const (
 	deployLogsContNamesAnnKey = "kapp.k14s.io/deploy-logs-container-names"
 )
 
-func (o *DeployOptions) showLogs(
+func (o *DeployOptions) showLogs(ctx context.Context,
 	coreClient kubernetes.Interface, identifiedResources ctlres.IdentifiedResources,
 	existingPodRs []ctlres.Resource, labelSelector labels.Selector, cancelCh chan struct{}, resourceNamespaces []string) {
 
@@ -561,7 +760,7 @@ func (o *DeployOptions) showLogs(
 
 	podWatcher := ctlres.FilteringPodWatcher{
 		podMatcherFunc,
-		identifiedResources.PodResources(labelSelector, resourceNamespaces),
+		identifiedResources.PodResources(ctx, labelSelector, resourceNamespaces),
 	}
 
 	contFilterFunc := func(pod corev1.Pod) []string {
@@ -577,7 +776,7 @@ func (o *DeployOptions) showLogs(
 	ctllogs.NewView(logOpts, podWatcher, contFilterFunc, coreClient, o.ui).Show(cancelCh)
 }
 
-func (o *DeployOptions) nsNames(resources []ctlres.Resource) []string {
+func resourceNsNames(resources []ctlres.Resource) []string {
 	uniqNames := map[string]struct{}{}
 	names := []string{}
 	for _, res := range resources {
@@ -594,9 +793,50 @@ func (o *DeployOptions) nsNames(resources []ctlres.Resource) []string {
 	return names
 }
 
-func (o *DeployOptions) presentDiffUI(graph *ctldgraph.ChangeGraph) error {
+func (o *DeployOptions) presentDiffUI(app ctlapp.App, graph *ctldgraph.ChangeGraph, conf ctlconf.Conf) error {
+	reportOpts, err := o.diffReportOpts(app, graph, conf)
+	if err != nil {
+		return err
+	}
+
 	opts := ctldiffui.ServerOpts{
-		DiffDataFunc: func() *ctldgraph.ChangeGraph { return graph },
+		ReportOptsFunc: func() ctldiffui.ReportOpts { return reportOpts },
 	}
 	return ctldiffui.NewServer(opts, o.ui).Run()
 }
+
+func (o *DeployOptions) approveInDiffUI(app ctlapp.App, graph *ctldgraph.ChangeGraph, conf ctlconf.Conf) error {
+	reportOpts, err := o.diffReportOpts(app, graph, conf)
+	if err != nil {
+		return err
+	}
+
+	opts := ctldiffui.ServerOpts{
+		ReportOptsFunc: func() ctldiffui.ReportOpts { return reportOpts },
+	}
+	return ctldiffui.NewServer(opts, o.ui).RunForApproval()
+}
+
+func (o *DeployOptions) writeDiffReport(app ctlapp.App, graph *ctldgraph.ChangeGraph, conf ctlconf.Conf) error {
+	if len(o.DiffFlags.ReportHTML) == 0 {
+		return nil
+	}
+
+	reportOpts, err := o.diffReportOpts(app, graph, conf)
+	if err != nil {
+		return err
+	}
+
+	err = ctldiffui.NewReport(reportOpts).WriteToFile(o.DiffFlags.ReportHTML)
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Wrote diff report to '%s'", o.DiffFlags.ReportHTML)
+	return nil
+}
+
+func (o *DeployOptions) diffReportOpts(app ctlapp.App, graph *ctldgraph.ChangeGraph, conf ctlconf.Conf) (ctldiffui.ReportOpts, error) {
+	title := fmt.Sprintf("kapp deploy: app '%s' (namespace: %s)", app.Name(), o.AppFlags.NamespaceFlags.Name)
+	return ctlcap.NewChangeSetReportOpts(title, graph, conf.DiffMaskRules(), o.DiffFlags.ChangeSetViewOpts)
+}
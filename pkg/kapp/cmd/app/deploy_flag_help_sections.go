@@ -23,6 +23,7 @@ var (
 		ExactMatch: []string{
 			"dangerous-allow-empty-list-of-resources",
 			"dangerous-override-ownership-of-existing-resources",
+			"dangerous-allow-deleting-protected-resource",
 			"ownership-override-allowed-apps",
 		},
 	}
@@ -44,6 +45,11 @@ var (
 		Title:      "Resource Mangling Flags:",
 		ExactMatch: []string{"into-ns", "map-ns"},
 	}
+	MultiClusterFlagGroup = cobrautil.FlagHelpSection{
+		Title:       "Multi-cluster Flags:",
+		PrefixMatch: "cluster-rollout",
+		ExactMatch:  []string{"targets-file"},
+	}
 	LogsFlagGroup = cobrautil.FlagHelpSection{
 		Title:       "Logs Flags:",
 		PrefixMatch: "logs",
@@ -64,6 +70,7 @@ func setDeployCmdFlags(cmd *cobra.Command) {
 		ResourceFilterFlagGroup,
 		ResourceValidationFlagGroup,
 		ResourceManglingFlagGroup,
+		MultiClusterFlagGroup,
 		LogsFlagGroup,
 		OtherFlagGroup,
 	}))
@@ -29,6 +29,13 @@ type DeployFlags struct {
 	AppMetadataFile string
 
 	DisableGKScoping bool
+
+	ServerSideDryRun bool
+
+	PlanOutput string
+	FromPlan   string
+
+	RollbackOnFailure bool
 }
 
 func (s *DeployFlags) Set(cmd *cobra.Command) {
@@ -48,7 +55,7 @@ func (s *DeployFlags) Set(cmd *cobra.Command) {
 	cmd.Flags().IntVar(&s.ExistingNonLabeledResourcesCheckConcurrency, "existing-non-labeled-resources-check-concurrency",
 		100, "Concurrency to check for existing non-labeled resources")
 	cmd.Flags().BoolVar(&s.OverrideOwnershipOfExistingResources, "dangerous-override-ownership-of-existing-resources",
-		false, "Steal existing resources from another app")
+		false, "Steal existing resources from another app (prefer adoptionRules in kapp config to adopt only specific resources)")
 	cmd.Flags().StringSliceVar(&s.OwnershipOverrideAllowedApps, "dangerous-ownership-override-allowed-apps", nil,
 		"Specify existing apps in the same namespace that existing resources can be stolen from")
 
@@ -63,4 +70,13 @@ func (s *DeployFlags) Set(cmd *cobra.Command) {
 
 	cmd.Flags().BoolVar(&s.DisableGKScoping, "dangerous-disable-gk-scoping",
 		false, "Disable scoping of resource searching to used GroupKinds")
+
+	cmd.Flags().BoolVar(&s.ServerSideDryRun, "diff-server-side-dry-run", false,
+		"Send add and update changes to the server as dry run requests and show server mutated resources in diff")
+
+	cmd.Flags().StringVar(&s.PlanOutput, "plan-output", "", "Set filename to write deploy plan to instead of applying changes")
+	cmd.Flags().StringVar(&s.FromPlan, "from-plan", "", "Apply deploy plan from file if cluster did not drift since plan was calculated")
+
+	cmd.Flags().BoolVar(&s.RollbackOnFailure, "rollback-on-failure", false,
+		"Re-apply resources of last successful app change if applying or waiting for changes fails")
 }
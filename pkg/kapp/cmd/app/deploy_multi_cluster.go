@@ -0,0 +1,239 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+)
+
+type multiClusterDeploy struct {
+	Target   DeployTarget
+	UI       ui.UI
+	PlanPath string
+
+	Result string
+	Err    error
+	// NoChanges is only known after changes are applied
+	NoChanges bool
+}
+
+// runMultiCluster calculates and shows changes for each cluster,
+// asks for a single confirmation and then applies changes to clusters.
+// Changes are saved as deploy plans so that changes applied to each cluster
+// are the same ones that were confirmed (otherwise cluster is reported as drifted).
+func (o *DeployOptions) runMultiCluster(targets []DeployTarget) error {
+	switch {
+	case len(o.DeployFlags.PlanOutput) > 0 || len(o.DeployFlags.FromPlan) > 0:
+		return fmt.Errorf("Expected no --plan-output or --from-plan to be specified when deploying to multiple clusters")
+	case o.DiffFlags.UI:
+		return fmt.Errorf("Expected no --diff-ui to be specified when deploying to multiple clusters")
+	case o.PreflightChecks != nil && len(o.PreflightChecks.String()) > 0:
+		return fmt.Errorf("Expected no preflight checks to be enabled when deploying to multiple clusters")
+	case o.MultiClusterFlags.RolloutConcurrency < 1:
+		return fmt.Errorf("Expected --cluster-rollout-concurrency to be greater than 0")
+	}
+
+	// Read resources once since they may come from stdin
+	inputResources, err := o.inputResources()
+	if err != nil {
+		return err
+	}
+
+	planDir, err := os.MkdirTemp("", "kapp-deploy-plans")
+	if err != nil {
+		return fmt.Errorf("Creating directory for deploy plans: %w", err)
+	}
+	defer os.RemoveAll(planDir)
+
+	var outputLock sync.Mutex
+	var deploys []*multiClusterDeploy
+	var targetDescs []string
+	allNoChanges := true
+
+	for i, target := range targets {
+		deploy := &multiClusterDeploy{
+			Target: target,
+			UI:     cmdcore.NewPrefixedUI(o.ui, target.Context+" | ", &outputLock),
+		}
+
+		clusterOpts := o.forTarget(deploy)
+		clusterOpts.ResourcesFunc = func() ([]ctlres.Resource, error) {
+			var resources []ctlres.Resource
+			for _, res := range inputResources {
+				resources = append(resources, res.DeepCopy())
+			}
+			return resources, nil
+		}
+
+		if !o.DiffFlags.Run {
+			deploy.PlanPath = filepath.Join(planDir, fmt.Sprintf("plan-%d.yml", i))
+			clusterOpts.DeployFlags.PlanOutput = deploy.PlanPath
+		}
+
+		err := clusterOpts.Run()
+		if err != nil {
+			if diffErr, ok := err.(DeployDiffExitStatus); ok {
+				allNoChanges = allNoChanges && diffErr.HasNoChanges
+			} else {
+				return fmt.Errorf("Calculating changes for cluster '%s': %w", target.Description(), err)
+			}
+		}
+
+		deploys = append(deploys, deploy)
+		targetDescs = append(targetDescs, target.Description())
+	}
+
+	if o.DiffFlags.Run {
+		if o.DiffFlags.ExitStatus {
+			return DeployDiffExitStatus{allNoChanges}
+		}
+		return nil
+	}
+
+	o.ui.PrintLinef("Changes will be applied to %d clusters (in order): %s",
+		len(deploys), strings.Join(targetDescs, ", "))
+
+	err = o.ui.AskForConfirmation()
+	if err != nil {
+		return err
+	}
+
+	o.applyToClusters(deploys)
+
+	o.printClustersSummary(deploys)
+
+	var errs []error
+	allNoChanges = true
+
+	for _, deploy := range deploys {
+		if deploy.Err != nil {
+			errs = append(errs, fmt.Errorf("Applying changes to cluster '%s': %w", deploy.Target.Description(), deploy.Err))
+		}
+		allNoChanges = allNoChanges && deploy.NoChanges
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	if o.ApplyFlags.ExitStatus {
+		return DeployApplyExitStatus{allNoChanges}
+	}
+	return nil
+}
+
+// applyToClusters applies previously calculated plans in listed order
+// with configured concurrency; once applying to a cluster fails,
+// remaining clusters are skipped unless configured otherwise
+func (o *DeployOptions) applyToClusters(deploys []*multiClusterDeploy) {
+	var wg sync.WaitGroup
+	var stateLock sync.Mutex
+	var failed bool
+
+	semaphore := make(chan struct{}, o.MultiClusterFlags.RolloutConcurrency)
+
+	for _, deploy := range deploys {
+		semaphore <- struct{}{}
+
+		stateLock.Lock()
+		skip := failed && !o.MultiClusterFlags.ContinueOnFailure
+		stateLock.Unlock()
+
+		if skip {
+			deploy.Result = "skipped (previous cluster failed)"
+			<-semaphore
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(deploy *multiClusterDeploy) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			// Confirmation was already given for all clusters
+			deploy.UI = ui.NewNonInteractiveUI(deploy.UI)
+
+			clusterOpts := o.forTarget(deploy)
+			clusterOpts.FileFlags = cmdtools.FileFlags{}
+			clusterOpts.ResourcesFunc = nil
+			clusterOpts.DeployFlags.FromPlan = deploy.PlanPath
+			clusterOpts.ApplyFlags.ExitStatus = true
+
+			err := clusterOpts.Run()
+			if applyErr, ok := err.(DeployApplyExitStatus); ok {
+				deploy.NoChanges = applyErr.hasNoChanges
+				err = nil
+			}
+
+			stateLock.Lock()
+			defer stateLock.Unlock()
+
+			if err != nil {
+				deploy.Result = "failed"
+				deploy.Err = err
+				failed = true
+			} else {
+				deploy.Result = "succeeded"
+			}
+		}(deploy)
+	}
+
+	wg.Wait()
+}
+
+func (o *DeployOptions) printClustersSummary(deploys []*multiClusterDeploy) {
+	table := uitable.Table{
+		Title:   "Clusters",
+		Content: "clusters",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Cluster"),
+			uitable.NewHeader("Result"),
+			uitable.NewHeader("Changes"),
+		},
+	}
+
+	for _, deploy := range deploys {
+		changes := "-"
+		if deploy.Result == "succeeded" {
+			changes = "applied"
+			if deploy.NoChanges {
+				changes = "none"
+			}
+		}
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(deploy.Target.Description()),
+			uitable.NewValueString(deploy.Result),
+			uitable.NewValueString(changes),
+		})
+	}
+
+	o.ui.PrintTable(table)
+}
+
+// forTarget returns copy of deploy options that targets single cluster
+func (o *DeployOptions) forTarget(deploy *multiClusterDeploy) *DeployOptions {
+	clusterOpts := *o
+	clusterOpts.ui = deploy.UI
+	clusterOpts.depsFactory = o.depsFactory.ForKubeconfig(deploy.Target.Kubeconfig, deploy.Target.Context, deploy.UI)
+	clusterOpts.MultiClusterFlags = MultiClusterFlags{}
+	// Preflight checks are configured against default cluster
+	clusterOpts.PreflightChecks = nil
+	return &clusterOpts
+}
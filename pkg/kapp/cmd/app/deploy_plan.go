@@ -0,0 +1,192 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	deployPlanVersion = "kapp.k14s.io/v1alpha1/DeployPlan"
+)
+
+// DeployPlan captures calculated changes for an app together with
+// resources they were calculated from, so that exactly the same
+// changes can be applied later (given cluster did not drift).
+type DeployPlan struct {
+	Version string `json:"version"`
+	App     string `json:"app"`
+	Nonce   string `json:"nonce"`
+
+	Changes   []DeployPlanChange       `json:"changes"`
+	Resources []map[string]interface{} `json:"resources"`
+}
+
+type DeployPlanChange struct {
+	Resource   string `json:"resource"`
+	ApplyOp    string `json:"applyOp"`
+	WaitOp     string `json:"waitOp"`
+	OpsDiffMD5 string `json:"opsDiffMD5"`
+
+	NewResource map[string]interface{} `json:"newResource,omitempty"`
+}
+
+type deployPlanClusterChange interface {
+	Resource() ctlres.Resource
+	ApplyOp() ctlcap.ClusterChangeApplyOp
+	WaitOp() ctlcap.ClusterChangeWaitOp
+	OpsDiff() ctldiff.OpsDiff
+}
+
+func NewDeployPlan(appName, nonce string, inputResources []ctlres.Resource, graph *ctldgraph.ChangeGraph) (DeployPlan, error) {
+	changes, err := newDeployPlanChanges(graph)
+	if err != nil {
+		return DeployPlan{}, err
+	}
+
+	plan := DeployPlan{
+		Version:   deployPlanVersion,
+		App:       appName,
+		Nonce:     nonce,
+		Changes:   changes,
+		Resources: []map[string]interface{}{},
+	}
+
+	for _, res := range inputResources {
+		plan.Resources = append(plan.Resources, res.DeepCopyRaw())
+	}
+
+	return plan, nil
+}
+
+func NewDeployPlanFromFile(path string) (DeployPlan, error) {
+	var plan DeployPlan
+
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return plan, fmt.Errorf("Reading deploy plan: %w", err)
+	}
+
+	err = json.Unmarshal(bs, &plan)
+	if err != nil {
+		return plan, fmt.Errorf("Unmarshaling deploy plan: %w", err)
+	}
+
+	if plan.Version != deployPlanVersion {
+		return plan, fmt.Errorf("Expected deploy plan version to be '%s' but was '%s'", deployPlanVersion, plan.Version)
+	}
+
+	return plan, nil
+}
+
+func newDeployPlanChanges(graph *ctldgraph.ChangeGraph) ([]DeployPlanChange, error) {
+	changes := []DeployPlanChange{}
+
+	for _, graphChange := range graph.All() {
+		change, ok := graphChange.Change.(deployPlanClusterChange)
+		if !ok {
+			return nil, fmt.Errorf("Expected change '%s' to be cluster change", graphChange.Change.Resource().Description())
+		}
+
+		planChange := DeployPlanChange{
+			Resource:   change.Resource().Description(),
+			ApplyOp:    string(change.ApplyOp()),
+			WaitOp:     string(change.WaitOp()),
+			OpsDiffMD5: change.OpsDiff().MinimalMD5(),
+		}
+
+		switch change.ApplyOp() {
+		case ctlcap.ClusterChangeApplyOpAdd, ctlcap.ClusterChangeApplyOpUpdate:
+			planChange.NewResource = change.Resource().DeepCopyRaw()
+		}
+
+		changes = append(changes, planChange)
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Resource < changes[j].Resource })
+
+	return changes, nil
+}
+
+func (p DeployPlan) WriteToFile(path string) error {
+	bs, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Marshaling deploy plan: %w", err)
+	}
+
+	err = os.WriteFile(path, bs, 0600)
+	if err != nil {
+		return fmt.Errorf("Writing deploy plan: %w", err)
+	}
+
+	return nil
+}
+
+func (p DeployPlan) InputResources() []ctlres.Resource {
+	var result []ctlres.Resource
+	for _, raw := range p.Resources {
+		result = append(result, ctlres.NewResourceUnstructured(unstructured.Unstructured{Object: raw}, ctlres.ResourceType{}))
+	}
+	return result
+}
+
+// CheckDrift verifies that changes calculated against current cluster state
+// match changes recorded in the plan.
+func (p DeployPlan) CheckDrift(graph *ctldgraph.ChangeGraph) error {
+	plannedChanges := map[string]DeployPlanChange{}
+	for _, change := range p.Changes {
+		plannedChanges[change.Resource] = change
+	}
+
+	changes, err := newDeployPlanChanges(graph)
+	if err != nil {
+		return err
+	}
+
+	var errStrs []string
+
+	for _, change := range changes {
+		plannedChange, found := plannedChanges[change.Resource]
+		if !found {
+			errStrs = append(errStrs, fmt.Sprintf("- %s: unexpected %s", change.Resource, change.ApplyOp))
+			continue
+		}
+
+		delete(plannedChanges, change.Resource)
+
+		if plannedChange.ApplyOp != change.ApplyOp || plannedChange.WaitOp != change.WaitOp {
+			errStrs = append(errStrs, fmt.Sprintf("- %s: expected ops %s/%s but calculated %s/%s", change.Resource,
+				plannedChange.ApplyOp, plannedChange.WaitOp, change.ApplyOp, change.WaitOp))
+			continue
+		}
+
+		if plannedChange.OpsDiffMD5 != change.OpsDiffMD5 {
+			errStrs = append(errStrs, fmt.Sprintf("- %s: expected diff md5 %s but calculated %s",
+				change.Resource, plannedChange.OpsDiffMD5, change.OpsDiffMD5))
+		}
+	}
+
+	for _, change := range p.Changes {
+		if _, found := plannedChanges[change.Resource]; found {
+			errStrs = append(errStrs, fmt.Sprintf("- %s: missing %s", change.Resource, change.ApplyOp))
+		}
+	}
+
+	if len(errStrs) > 0 {
+		return fmt.Errorf("Refusing to apply deploy plan since calculated changes differ from planned changes "+
+			"(hint: cluster state or deploy flags changed since plan was calculated):\n%s", strings.Join(errStrs, "\n"))
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+
+	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+// rollback re-applies resources recorded for last successful app change.
+// Changes are calculated and applied via regular change graph
+// so that change rules are respected when going back.
+func (o *DeployOptions) rollback(ctx context.Context, applyErr error, app ctlapp.App, prep ctlapp.Preparation,
+	labeledResources *ctlres.LabeledResources, resourceFilter ctlres.ResourceFilter, conf ctlconf.Conf,
+	supportObjs FactorySupportObjs, nsNames []string) error {
+
+	o.ui.ErrorLinef("Applying changes failed: %s", applyErr)
+
+	var reason string
+
+	prevChangeName, err := o.rollbackToLastSuccessfulChange(ctx, app, prep, labeledResources, resourceFilter, conf, supportObjs, nsNames)
+	if err != nil {
+		reason = fmt.Sprintf("rollback failed: %s", err)
+	} else {
+		reason = fmt.Sprintf("rolled back to app change '%s'", prevChangeName)
+	}
+
+	return ctlapp.ChangeFailedError{Err: fmt.Errorf("%w (%s)", applyErr, reason), Reason: reason}
+}
+
+func (o *DeployOptions) rollbackToLastSuccessfulChange(ctx context.Context, app ctlapp.App, prep ctlapp.Preparation,
+	labeledResources *ctlres.LabeledResources, resourceFilter ctlres.ResourceFilter, failedConf ctlconf.Conf,
+	supportObjs FactorySupportObjs, nsNames []string) (string, error) {
+
+	changes, err := app.Changes()
+	if err != nil {
+		return "", err
+	}
+
+	var prevChange ctlapp.Change
+
+	// Changes are sorted with oldest first
+	for i := len(changes) - 1; i >= 0; i-- {
+		successful := changes[i].Meta().Successful
+		if successful != nil && *successful {
+			prevChange = changes[i]
+			break
+		}
+	}
+
+	if prevChange == nil {
+		return "", fmt.Errorf("Expected to find previous successful app change")
+	}
+
+	recorded, found, err := prevChange.Resources()
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("Expected app change '%s' to have recorded resources", prevChange.Name())
+	}
+
+	o.ui.PrintLinef("Rolling back to app change '%s'", prevChange.Name())
+
+	// Recorded resources are prepared with flags that were used for previous change
+	prep = o.recordedPreparation(supportObjs.ResourceTypes, recorded.Opts)
+	resourceFilter = recordedResourceFilter(recorded.Opts)
+
+	prevResources, conf, prevNsNames, prevGKs, err := o.newResources(recorded.Resources, prep, labeledResources, resourceFilter)
+	if err != nil {
+		return "", err
+	}
+
+	usedGKs, err := o.newAndUsedGKs(prevGKs, app)
+	if err != nil {
+		return "", err
+	}
+
+	existingResources, _, err := o.existingResources(prevResources, labeledResources, resourceFilter, conf,
+		supportObjs.Apps, usedGKs, append(nsNames, prevNsNames...), false)
+	if err != nil {
+		return "", err
+	}
+
+	clusterChangeSet, clusterChangesGraph, _, _, err :=
+		o.calculateAndPresentChanges(existingResources, prevResources, conf, supportObjs)
+	if err != nil {
+		return "", err
+	}
+
+	// Resources created by failed change may be protected by its config
+	// (e.g. newly added PVC), hence check against both configs
+	for _, protectionConf := range []ctlconf.Conf{failedConf, conf} {
+		err = o.DeletionProtectionFlags.DeletionProtection(protectionConf).Check(clusterChangesGraph)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = o.RiskFlags.RiskCheck(conf).Check(clusterChangesGraph)
+	if err != nil {
+		return "", err
+	}
+
+	err = clusterChangeSet.Apply(ctx, clusterChangesGraph)
+	if err != nil {
+		return "", err
+	}
+
+	return prevChange.Name(), nil
+}
@@ -0,0 +1,336 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	"github.com/spf13/cobra"
+)
+
+type DriftOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags            Flags
+	ResourceFilterFlags cmdtools.ResourceFilterFlags
+	ResourceTypesFlags  ResourceTypesFlags
+
+	Changes          bool
+	TextDiffViewOpts ctldiff.TextDiffViewOpts
+	ExitStatus       bool
+	Watch            bool
+	WatchInterval    time.Duration
+}
+
+func NewDriftOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *DriftOptions {
+	return &DriftOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewDriftCmd(o *DriftOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Show app resources changed outside of kapp",
+		Long: `Show app resources changed outside of kapp
+
+Last applied copy of each resource is compared with its live state
+(after applying rebase rules). Fields defaulted or populated by the server
+are not considered drift.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Annotations: map[string]string{
+			cmdcore.AppHelpGroup.Key: cmdcore.AppHelpGroup.Value,
+		},
+		Example: `
+  # Show drifted resources in app 'app1'
+  kapp drift -a app1
+
+  # Show drifted resources with their diffs
+  kapp drift -a app1 -c
+
+  # Exit with status 3 if any resources drifted (2 otherwise)
+  kapp drift -a app1 --exit-status
+
+  # Keep checking for drift every 30s
+  kapp drift -a app1 --watch --watch-interval 30s`,
+	}
+	o.AppFlags.Set(cmd, flagsFactory)
+	o.ResourceFilterFlags.Set(cmd)
+	o.ResourceTypesFlags.Set(cmd)
+	cmd.Flags().BoolVarP(&o.Changes, "diff-changes", "c", false, "Show text diff for drifted resources")
+	cmd.Flags().IntVar(&o.TextDiffViewOpts.Context, "diff-context", 2, "Show number of lines around changed lines")
+	cmd.Flags().BoolVar(&o.TextDiffViewOpts.Mask, "diff-mask", true, "Apply masking rules")
+	cmd.Flags().BoolVar(&o.ExitStatus, "exit-status", false, "Return specific exit status based on whether resources drifted")
+	cmd.Flags().BoolVar(&o.Watch, "watch", false, "Keep checking for drift until interrupted")
+	cmd.Flags().DurationVar(&o.WatchInterval, "watch-interval", 10*time.Second, "Amount of time to sleep between checks when watching")
+	return cmd
+}
+
+func (o *DriftOptions) Run() error {
+	if o.Watch && o.ExitStatus {
+		return fmt.Errorf("Expected only one of --watch or --exit-status to be specified")
+	}
+
+	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
+
+	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
+	if err != nil {
+		return err
+	}
+
+	exists, notExistsMsg, err := app.Exists()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%s", notExistsMsg)
+	}
+
+	usedGVs, err := app.UsedGVs()
+	if err != nil {
+		return err
+	}
+
+	failingAPIServicesPolicy.MarkRequiredGVs(usedGVs)
+
+	conf, _, err := lastChangeConf(app)
+	if err != nil {
+		return err
+	}
+
+	var prevReport string
+
+	for {
+		driftedChanges, views, err := o.calculateDrift(app, supportObjs, conf)
+		if err != nil {
+			return err
+		}
+
+		if !o.Watch {
+			o.print(app, driftedChanges, views, conf)
+
+			if o.ExitStatus {
+				return DriftExitStatus{HasDrift: len(driftedChanges) > 0}
+			}
+			return nil
+		}
+
+		// Only print when drift changes to avoid repeating same output
+		report := o.report(views)
+		if report != prevReport {
+			o.print(app, driftedChanges, views, conf)
+			prevReport = report
+		}
+
+		time.Sleep(o.WatchInterval)
+	}
+}
+
+// lastChangeConf includes kapp config provided during last deploy (if it was recorded)
+// so that custom rules are taken into account when resources are not provided
+func lastChangeConf(app ctlapp.App) (ctlconf.Conf, bool, error) {
+	recorded, found, err := lastRecordedResources(app)
+	if err != nil {
+		return ctlconf.Conf{}, false, err
+	}
+
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(recorded.Resources)
+	if err != nil {
+		return ctlconf.Conf{}, false, err
+	}
+
+	return conf, found, nil
+}
+
+// lastRecordedResources returns resources recorded during last deploy.
+// Deletes do not record resources, hence they are skipped.
+func lastRecordedResources(app ctlapp.App) (ctlapp.RecordedResources, bool, error) {
+	changes, err := app.Changes()
+	if err != nil {
+		return ctlapp.RecordedResources{}, false, err
+	}
+
+	// Changes are sorted with oldest first
+	for i := len(changes) - 1; i >= 0; i-- {
+		if changes[i].Meta().Description == deleteChangeDescription {
+			continue
+		}
+		return changes[i].Resources()
+	}
+
+	return ctlapp.RecordedResources{}, false, nil
+}
+
+type DriftResourceView struct {
+	Resource ctlres.Resource
+	State    string
+	Paths    []string
+}
+
+const (
+	driftStateDrifted = "drifted"
+	driftStateInSync  = "in sync"
+	driftStateUnknown = "unknown"
+)
+
+func (o *DriftOptions) calculateDrift(app ctlapp.App, supportObjs FactorySupportObjs,
+	conf ctlconf.Conf) ([]ctldiff.Change, []DriftResourceView, error) {
+
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta, err := app.Meta()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resources, err := supportObjs.IdentifiedResources.List(labelSelector, nil, ctlres.IdentifiedResourcesListOpts{
+		ResourceNamespaces: meta.LastChange.Namespaces})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changeFactory := ctldiff.NewChangeFactory(conf.RebaseMods(), conf.DiffAgainstLastAppliedFieldExclusionMods(),
+		conf.DiffAgainstExistingFieldExclusionMods(), ctldiff.ChangeOpts{})
+
+	var driftedChanges []ctldiff.Change
+	var views []DriftResourceView
+
+	for _, res := range resourceFilter.Apply(resources) {
+		// Hooks are recreated on every deploy hence do not drift
+		if _, isHook := res.Annotations()[ctlres.HookAnnKey]; isHook {
+			continue
+		}
+
+		change, found, err := changeFactory.NewDriftChange(res)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Calculating drift for %s: %w", res.Description(), err)
+		}
+
+		switch {
+		case !found && len(res.OwnerRefs()) > 0:
+			// Resources created by controllers (e.g. Pods) are not applied by kapp
+			continue
+		case !found:
+			views = append(views, DriftResourceView{Resource: res, State: driftStateUnknown})
+		case change.Op() == ctldiff.ChangeOpUpdate:
+			driftedChanges = append(driftedChanges, change)
+			views = append(views, DriftResourceView{Resource: res, State: driftStateDrifted, Paths: ctldiff.DriftedPaths(change)})
+		default:
+			views = append(views, DriftResourceView{Resource: res, State: driftStateInSync})
+		}
+	}
+
+	return driftedChanges, views, nil
+}
+
+func (o *DriftOptions) print(app ctlapp.App, driftedChanges []ctldiff.Change,
+	views []DriftResourceView, conf ctlconf.Conf) {
+
+	if o.Changes {
+		for _, change := range driftedChanges {
+			textDiffView := ctldiff.NewTextDiffView(change.ConfigurableTextDiff(), conf.DiffMaskRules(), o.TextDiffViewOpts)
+			o.ui.BeginLinef("@@ drifted %s @@\n", change.NewOrExistingResource().Description())
+			o.ui.PrintBlock([]byte(textDiffView.String()))
+		}
+	}
+
+	DriftView{Source: fmt.Sprintf("app '%s'", app.Name()), Views: views}.Print(o.ui)
+}
+
+func (o *DriftOptions) report(views []DriftResourceView) string {
+	var lines []string
+	for _, view := range views {
+		lines = append(lines, fmt.Sprintf("%s %s %s", view.Resource.Description(), view.State, strings.Join(view.Paths, ",")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+type DriftView struct {
+	Source string
+	Views  []DriftResourceView
+}
+
+func (v DriftView) Print(ui ui.UI) {
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Drift in %s", v.Source),
+		Content: "resources",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Namespace"),
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Kind"),
+			uitable.NewHeader("Drift"),
+			uitable.NewHeader("Fields"),
+		},
+
+		SortBy: []uitable.ColumnSort{
+			{Column: 0, Asc: true},
+			{Column: 1, Asc: true},
+			{Column: 2, Asc: true},
+		},
+
+		Notes: []string{"Drift 'unknown' means resource does not have last applied copy"},
+	}
+
+	var numDrifted int
+
+	for _, view := range v.Views {
+		driftVal := uitable.ValueFmt{V: uitable.NewValueString(view.State), Error: view.State == driftStateDrifted}
+		if view.State == driftStateDrifted {
+			numDrifted++
+		}
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			cmdcore.NewValueNamespace(view.Resource.Namespace()),
+			uitable.NewValueString(view.Resource.Name()),
+			uitable.NewValueString(view.Resource.Kind()),
+			driftVal,
+			uitable.NewValueStrings(view.Paths),
+		})
+	}
+
+	table.Notes = append(table.Notes, fmt.Sprintf("%d drifted", numDrifted))
+
+	ui.PrintTable(table)
+}
+
+type DriftExitStatus struct {
+	HasDrift bool
+}
+
+var _ ExitStatus = DriftExitStatus{}
+
+func (d DriftExitStatus) Error() string {
+	desc := "no drifted resources"
+	if d.HasDrift {
+		desc = "drifted resources"
+	}
+	return fmt.Sprintf("Exiting after finding %s (exit status %d)", desc, d.ExitStatus())
+}
+
+func (d DriftExitStatus) ExitStatus() int {
+	if d.HasDrift {
+		return 3
+	}
+	return 2
+}
@@ -0,0 +1,130 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	helmReleaseSecretType     = "helm.sh/release.v1"
+	helmReleaseDeployedStatus = "deployed"
+)
+
+var (
+	helmReleaseGzipMagic = []byte{0x1f, 0x8b, 0x08}
+)
+
+// HelmRelease holds subset of Helm v3 release fields
+// necessary to import its resources into an app
+type HelmRelease struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   int    `json:"version"`
+	Manifest  string `json:"manifest"`
+	Info      struct {
+		Status string `json:"status"`
+	} `json:"info"`
+}
+
+// HelmReleases finds Helm v3 releases stored as secrets
+// (Helm's default storage driver); other storage drivers are not supported
+type HelmReleases struct {
+	coreClient kubernetes.Interface
+	namespace  string
+}
+
+func NewHelmReleases(coreClient kubernetes.Interface, namespace string) HelmReleases {
+	return HelmReleases{coreClient, namespace}
+}
+
+// LastDeployed returns latest revision of a release that is in deployed state
+func (r HelmReleases) LastDeployed(name string) (HelmRelease, error) {
+	listOpts := metav1.ListOptions{
+		LabelSelector: labels.Set{"owner": "helm", "name": name}.String(),
+	}
+
+	secrets, err := r.coreClient.CoreV1().Secrets(r.namespace).List(context.TODO(), listOpts)
+	if err != nil {
+		return HelmRelease{}, fmt.Errorf("Listing Helm release secrets: %w", err)
+	}
+
+	var lastSecret *corev1.Secret
+	var lastVersion int
+
+	for i, secret := range secrets.Items {
+		if secret.Type != helmReleaseSecretType || secret.Labels["status"] != helmReleaseDeployedStatus {
+			continue
+		}
+		version, err := strconv.Atoi(secret.Labels["version"])
+		if err != nil {
+			return HelmRelease{}, fmt.Errorf("Parsing version of Helm release secret '%s': %w", secret.Name, err)
+		}
+		if lastSecret == nil || version > lastVersion {
+			lastSecret = &secrets.Items[i]
+			lastVersion = version
+		}
+	}
+
+	if lastSecret == nil {
+		configMaps, err := r.coreClient.CoreV1().ConfigMaps(r.namespace).List(context.TODO(), listOpts)
+		if err != nil {
+			return HelmRelease{}, fmt.Errorf("Listing Helm release configmaps: %w", err)
+		}
+		if len(configMaps.Items) > 0 {
+			return HelmRelease{}, fmt.Errorf("Expected Helm release '%s' in namespace '%s' to be stored in secrets, "+
+				"but found it in configmaps (Helm's configmap storage driver is not supported)", name, r.namespace)
+		}
+		return HelmRelease{}, fmt.Errorf("Expected to find deployed Helm release '%s' in namespace '%s'", name, r.namespace)
+	}
+
+	release, err := r.decode(lastSecret.Data["release"])
+	if err != nil {
+		return HelmRelease{}, fmt.Errorf("Decoding Helm release secret '%s': %w", lastSecret.Name, err)
+	}
+
+	return release, nil
+}
+
+// decode reverses Helm's encoding of a release: JSON, gzipped and base64 encoded
+func (HelmReleases) decode(data []byte) (HelmRelease, error) {
+	decoded, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return HelmRelease{}, err
+	}
+
+	if bytes.HasPrefix(decoded, helmReleaseGzipMagic) {
+		reader, err := gzip.NewReader(bytes.NewReader(decoded))
+		if err != nil {
+			return HelmRelease{}, err
+		}
+		defer reader.Close()
+
+		decoded, err = io.ReadAll(reader)
+		if err != nil {
+			return HelmRelease{}, err
+		}
+	}
+
+	var release HelmRelease
+
+	err = json.Unmarshal(decoded, &release)
+	if err != nil {
+		return HelmRelease{}, err
+	}
+
+	return release, nil
+}
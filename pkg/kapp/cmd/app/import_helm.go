@@ -0,0 +1,384 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	"carvel.dev/kapp/pkg/kapp/logger"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	importHelmChangeDescription = "import-helm"
+	importHelmAppLabelKey       = "kapp.k14s.io/app"
+
+	// Annotations Helm adds to live resources
+	// (e.g. meta.helm.sh/release-name) that are not part of release manifest
+	helmAnnPrefix = "meta.helm.sh/"
+)
+
+type ImportHelmOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags           Flags
+	ResourceTypesFlags ResourceTypesFlags
+	LabelFlags         LabelFlags
+	ReleaseName        string
+}
+
+func NewImportHelmOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *ImportHelmOptions {
+	return &ImportHelmOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewImportHelmCmd(o *ImportHelmOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-helm",
+		Short: "Import Helm release into a new app",
+		Long: `Import Helm release into a new app.
+
+Only ownership labels and annotations of release's live resources are updated,
+hence their specs (including selectors and pod templates) are left as is.
+Helm's 'meta.helm.sh/*' annotations are removed from imported resources.
+
+Import fails if default ownership label or label scoping rules would change specs
+of release's resources on subsequent deploys (e.g. immutable Deployment selectors),
+unless such resources are annotated with 'kapp.k14s.io/disable-default-ownership-label-rules'
+and 'kapp.k14s.io/disable-default-label-scoping-rules' in the release.
+
+Only releases stored in Secrets (Helm's default storage driver) are supported.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Annotations: map[string]string{
+			TTYByDefaultKey: "",
+		},
+		Example: `
+  # Import Helm release 'rel1' in namespace 'ns1' into app 'app1'
+  kapp app import-helm --release rel1 -n ns1 -a app1
+
+  # Afterwards deploy rendered chart with kapp
+  helm template rel1 chart/ -n ns1 | kapp deploy -n ns1 -a app1 -f -`,
+	}
+
+	o.AppFlags.Set(cmd, flagsFactory)
+	o.ResourceTypesFlags.Set(cmd)
+	o.LabelFlags.Set(cmd)
+
+	cmd.Flags().StringVar(&o.ReleaseName, "release", "", "Set Helm release name to import")
+
+	return cmd
+}
+
+func (o *ImportHelmOptions) Run() error {
+	if len(o.ReleaseName) == 0 {
+		return fmt.Errorf("Expected Helm release name to be specified via --release")
+	}
+
+	app, supportObjs, err := Factory(o.depsFactory,
+		o.AppFlags, o.ResourceTypesFlags, o.logger)
+	if err != nil {
+		return err
+	}
+
+	exists, _, err := app.Exists()
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return fmt.Errorf("Expected %s to not exist (hint: Helm releases can only be imported into new apps)", app.Description())
+	}
+
+	nsName := o.AppFlags.NamespaceFlags.Name
+
+	release, err := NewHelmReleases(supportObjs.CoreClient, nsName).LastDeployed(o.ReleaseName)
+	if err != nil {
+		return err
+	}
+
+	resources, err := ctlres.NewResourcesFromBytes([]byte(release.Manifest))
+	if err != nil {
+		return fmt.Errorf("Parsing manifest of Helm release '%s': %w", release.Name, err)
+	}
+
+	recordedResources := &ctlapp.RecordedResources{}
+	for _, res := range resources {
+		recordedResources.Resources = append(recordedResources.Resources, res.DeepCopy())
+	}
+
+	resources, conf, err := ctlconf.NewConfFromResourcesWithDefaults(resources)
+	if err != nil {
+		return err
+	}
+
+	resources, liveResources, err := o.liveResources(resources, nsName, supportObjs)
+	if err != nil {
+		return err
+	}
+
+	err = o.checkLabelRules(resources, conf, supportObjs)
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Importing Helm release '%s' (revision %d, %d resources) into %s",
+		release.Name, release.Version, len(liveResources), app.Description())
+
+	err = o.ui.AskForConfirmation()
+	if err != nil {
+		return err
+	}
+
+	appLabels, err := o.LabelFlags.AsMap()
+	if err != nil {
+		return err
+	}
+
+	_, err = app.CreateOrUpdate("", appLabels, false)
+	if err != nil {
+		return err
+	}
+
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return err
+	}
+
+	labeledResources := ctlres.NewLabeledResources(labelSelector, supportObjs.IdentifiedResources, o.logger)
+
+	var usedGVs []schema.GroupVersion
+	for _, res := range liveResources {
+		usedGVs = append(usedGVs, res.GroupVersion())
+	}
+
+	err = app.UpdateUsedGVsAndGKs(usedGVs, NewUsedGKsScope(liveResources).GKs())
+	if err != nil {
+		return err
+	}
+
+	touch := ctlapp.Touch{
+		App:                 app,
+		Description:         importHelmChangeDescription,
+		Namespaces:          resourceNsNames(liveResources),
+		Resources:           recordedResources,
+		AppChangesMaxToKeep: ctlapp.AppChangesMaxToKeepDefault,
+		WarningFunc:         func(err error) { o.ui.ErrorLinef("Warning: %s (rollback to this change will not be possible)", err) },
+	}
+
+	err = touch.Do(func() error {
+		return o.labelResources(resources, liveResources, labeledResources, conf, supportObjs)
+	})
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Imported Helm release '%s' (Helm release secrets were left untouched "+
+		"and can be removed once app is deployed with kapp)", release.Name)
+
+	return nil
+}
+
+// liveResources makes sure that import only takes ownership
+// of release's live resources instead of creating missing ones.
+// Returned release resources (with namespaces set) and live resources are in the same order.
+func (o *ImportHelmOptions) liveResources(resources []ctlres.Resource,
+	nsName string, supportObjs FactorySupportObjs) ([]ctlres.Resource, []ctlres.Resource, error) {
+
+	var nsResources, liveResources []ctlres.Resource
+	var missingDescs []string
+
+	for _, res := range resources {
+		res = res.DeepCopy()
+
+		if len(res.Namespace()) == 0 {
+			resType, err := supportObjs.ResourceTypes.Find(res)
+			if err != nil {
+				return nil, nil, err
+			}
+			if resType.Namespaced() {
+				res.SetNamespace(nsName)
+			}
+		}
+
+		liveRes, found, err := supportObjs.IdentifiedResources.Exists(res, ctlres.ExistsOpts{})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !found {
+			missingDescs = append(missingDescs, res.Description())
+			continue
+		}
+
+		nsResources = append(nsResources, res)
+		liveResources = append(liveResources, liveRes)
+	}
+
+	if len(missingDescs) > 0 {
+		return nil, nil, fmt.Errorf("Expected Helm release resources to exist, but did not find: %s",
+			strings.Join(missingDescs, ", "))
+	}
+
+	return nsResources, liveResources, nil
+}
+
+// checkLabelRules makes sure that subsequent deploys do not change specs of imported
+// resources (e.g. immutable selectors) since only their metadata is updated during import
+func (o *ImportHelmOptions) checkLabelRules(resources []ctlres.Resource,
+	conf ctlconf.Conf, supportObjs FactorySupportObjs) error {
+
+	// App label value is not known until app is created,
+	// though it does not affect which fields label rules change
+	labelSelector := labels.Set{importHelmAppLabelKey: importHelmChangeDescription}.AsSelector()
+	labeledResources := ctlres.NewLabeledResources(labelSelector, supportObjs.IdentifiedResources, o.logger)
+
+	removeLabelsMod := ctlres.FieldRemoveMod{
+		ResourceMatcher: ctlres.AllMatcher{},
+		Path:            ctlres.NewPathFromStrings([]string{"metadata", "labels"}),
+	}
+
+	var changedDescs []string
+
+	for _, res := range resources {
+		origRes := res.DeepCopy()
+		labeledRes := res.DeepCopy()
+
+		err := labeledResources.Prepare([]ctlres.Resource{labeledRes}, conf.OwnershipLabelMods(),
+			conf.LabelScopingMods(true), conf.AdditionalLabels())
+		if err != nil {
+			return err
+		}
+
+		for _, r := range []ctlres.Resource{origRes, labeledRes} {
+			err := removeLabelsMod.Apply(r)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !labeledRes.Equal(origRes) {
+			changedDescs = append(changedDescs, res.Description())
+		}
+	}
+
+	if len(changedDescs) > 0 {
+		return fmt.Errorf("Expected Helm release resources to not be changed by label rules on subsequent deploys, "+
+			"but these would be: %s (hint: annotate them with 'kapp.k14s.io/disable-default-ownership-label-rules' "+
+			"and 'kapp.k14s.io/disable-default-label-scoping-rules' in the release before importing)",
+			strings.Join(changedDescs, ", "))
+	}
+
+	return nil
+}
+
+// labelResources only patches ownership labels and annotations so that
+// live resources' specs (e.g. immutable selectors) and any drift are preserved
+func (o *ImportHelmOptions) labelResources(resources, liveResources []ctlres.Resource,
+	labeledResources *ctlres.LabeledResources, conf ctlconf.Conf, supportObjs FactorySupportObjs) error {
+
+	noLabelScopingMods := func(map[string]string) []ctlres.StringMapAppendMod { return nil }
+
+	changeFactory := ctldiff.NewChangeFactory(conf.RebaseMods(), conf.DiffAgainstLastAppliedFieldExclusionMods(),
+		conf.DiffAgainstExistingFieldExclusionMods(), ctldiff.ChangeOpts{})
+
+	for i, liveRes := range liveResources {
+		appliedRes := resources[i].DeepCopy()
+
+		err := labeledResources.Prepare([]ctlres.Resource{appliedRes}, conf.OwnershipLabelMods(),
+			conf.LabelScopingMods(true), conf.AdditionalLabels())
+		if err != nil {
+			return err
+		}
+
+		res := liveRes.DeepCopy()
+
+		err = labeledResources.Prepare([]ctlres.Resource{res}, conf.OwnershipLabelMods(),
+			noLabelScopingMods, conf.AdditionalLabels())
+		if err != nil {
+			return err
+		}
+
+		var helmAnnKeys []string
+
+		for k := range res.Annotations() {
+			if strings.HasPrefix(k, helmAnnPrefix) {
+				helmAnnKeys = append(helmAnnKeys, k)
+			}
+		}
+
+		for _, k := range helmAnnKeys {
+			err := ctlres.FieldRemoveMod{
+				ResourceMatcher: ctlres.AllMatcher{},
+				Path:            ctlres.NewPathFromStrings([]string{"metadata", "annotations", k}),
+			}.Apply(res)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Record release resource as last applied (as deploy would) so that
+		// subsequent deploys diff against it instead of against live resource
+		// (e.g. with fields defaulted by the server)
+		resWithHistory := changeFactory.NewResourceWithHistory(res)
+
+		if resWithHistory.AllowsRecordingLastApplied() {
+			applyChange, err := resWithHistory.CalculateChange(appliedRes)
+			if err != nil {
+				return fmt.Errorf("Calculating change for %s: %w", res.Description(), err)
+			}
+
+			recordedRes, madeAnyModifications, err := resWithHistory.RecordLastAppliedResource(applyChange)
+			if err != nil {
+				return fmt.Errorf("Recording last applied resource for %s: %w", res.Description(), err)
+			}
+
+			if madeAnyModifications {
+				res = recordedRes
+			}
+		}
+
+		err = ctlres.NewIdentityAnnotation(res).AddMod().Apply(res)
+		if err != nil {
+			return err
+		}
+
+		anns := map[string]interface{}{}
+		for k, v := range res.Annotations() {
+			anns[k] = v
+		}
+		for _, k := range helmAnnKeys {
+			// Null removes annotation in a merge patch
+			anns[k] = nil
+		}
+
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels":      res.Labels(),
+				"annotations": anns,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = supportObjs.IdentifiedResources.Patch(res, types.MergePatchType, patch, ctlres.PatchOpts{})
+		if err != nil {
+			return fmt.Errorf("Labeling %s: %w", res.Description(), err)
+		}
+	}
+
+	return nil
+}
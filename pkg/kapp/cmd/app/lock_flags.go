@@ -0,0 +1,17 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type LockFlags struct {
+	ForceUnlock bool
+}
+
+func (s *LockFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&s.ForceUnlock, "force-unlock", false,
+		"Take over app lock held by another kapp run (use only when it's no longer running)")
+}
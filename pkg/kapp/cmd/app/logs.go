@@ -4,6 +4,8 @@
 package app
 
 import (
+	"context"
+
 	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
 	"carvel.dev/kapp/pkg/kapp/logger"
 	ctllogs "carvel.dev/kapp/pkg/kapp/logs"
@@ -71,7 +73,7 @@ func (o *LogsOptions) Run() error {
 			}
 			return true
 		},
-		supportObjs.IdentifiedResources.PodResources(labelSelector, nil),
+		supportObjs.IdentifiedResources.PodResources(context.Background(), labelSelector, nil),
 	}
 
 	contFilter := func(_ corev1.Pod) []string {
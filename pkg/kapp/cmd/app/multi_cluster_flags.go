@@ -0,0 +1,107 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+const (
+	deployTargetsAPIVersion = "kapp.k14s.io/v1alpha1"
+	deployTargetsKind       = "DeployTargets"
+)
+
+type MultiClusterFlags struct {
+	TargetsFile        string
+	RolloutConcurrency int
+	ContinueOnFailure  bool
+}
+
+func (s *MultiClusterFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&s.TargetsFile, "targets-file", "",
+		"Set file listing clusters to deploy to (alternative to specifying several contexts via --kubeconfig-context a,b)")
+	cmd.Flags().IntVar(&s.RolloutConcurrency, "cluster-rollout-concurrency", 1,
+		"Maximum number of clusters to apply changes to concurrently (clusters are started in listed order)")
+	cmd.Flags().BoolVar(&s.ContinueOnFailure, "cluster-rollout-continue-on-failure", false,
+		"Continue applying changes to remaining clusters after failing to apply changes to a cluster")
+}
+
+// Targets returns clusters to deploy to if more than one cluster is configured
+func (s *MultiClusterFlags) Targets(depsFactory cmdcore.DepsFactory) ([]DeployTarget, error) {
+	contexts, err := depsFactory.KubeconfigContexts()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.TargetsFile) > 0 {
+		if len(contexts) > 1 {
+			return nil, fmt.Errorf("Expected only one of --targets-file or multiple contexts via --kubeconfig-context to be specified")
+		}
+		targets, err := NewDeployTargetsFromFile(s.TargetsFile)
+		if err != nil {
+			return nil, err
+		}
+		return targets.Targets, nil
+	}
+
+	if len(contexts) < 2 {
+		return nil, nil
+	}
+
+	var targets []DeployTarget
+	for _, context := range contexts {
+		targets = append(targets, DeployTarget{Context: context})
+	}
+	return targets, nil
+}
+
+type DeployTargets struct {
+	cmdcore.ConfigFileHeader
+
+	Targets []DeployTarget `json:"targets"`
+}
+
+type DeployTarget struct {
+	// Context is a name of kubeconfig context
+	Context string `json:"context"`
+	// Kubeconfig is a path to kubeconfig file (defaults to --kubeconfig)
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+}
+
+func NewDeployTargetsFromFile(path string) (DeployTargets, error) {
+	var targets DeployTargets
+
+	err := cmdcore.ConfigFile{Path: path, Description: "targets file"}.Read(&targets)
+	if err != nil {
+		return DeployTargets{}, err
+	}
+
+	return targets, nil
+}
+
+func (t DeployTargets) Validate() error {
+	err := t.ConfigFileHeader.Validate(deployTargetsAPIVersion, deployTargetsKind)
+	if err != nil {
+		return err
+	}
+	if len(t.Targets) == 0 {
+		return fmt.Errorf("Expected at least one target")
+	}
+	for i, target := range t.Targets {
+		if len(target.Context) == 0 {
+			return fmt.Errorf("Validating target %d: Expected context to be non-empty", i)
+		}
+	}
+	return nil
+}
+
+func (t DeployTarget) Description() string {
+	if len(t.Kubeconfig) > 0 {
+		return fmt.Sprintf("%s (kubeconfig: %s)", t.Context, t.Kubeconfig)
+	}
+	return t.Context
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+// UseRecordedResources configures deploy to use resources recorded for an app change
+// together with flags that were in effect when they were deployed
+func (o *DeployOptions) UseRecordedResources(recorded ctlapp.RecordedResources) {
+	o.ResourcesFunc = func() ([]ctlres.Resource, error) { return recorded.Resources, nil }
+	o.DeployFlags.IntoNamespace = recorded.Opts.IntoNamespace
+	o.DeployFlags.MapNamespaces = recorded.Opts.MapNamespaces
+
+	resourceFilter := recordedResourceFilter(recorded.Opts)
+	o.recordedResourceFilter = &resourceFilter
+}
+
+func (o *DeployOptions) resourceFilter() (ctlres.ResourceFilter, error) {
+	if o.recordedResourceFilter != nil {
+		return *o.recordedResourceFilter, nil
+	}
+	return o.ResourceFilterFlags.ResourceFilter()
+}
+
+// recordedResources copies resources as provided since preparation modifies them in place
+func (o *DeployOptions) recordedResources(resources []ctlres.Resource, resourceFilter ctlres.ResourceFilter) *ctlapp.RecordedResources {
+	var copiedResources []ctlres.Resource
+	for _, res := range resources {
+		copiedResources = append(copiedResources, res.DeepCopy())
+	}
+
+	boolFilter := resourceFilter.BoolFilter
+	resourceFilter.BoolFilter = nil
+
+	return &ctlapp.RecordedResources{
+		Resources: copiedResources,
+		Opts: ctlapp.RecordedResourcesOpts{
+			IntoNamespace:  o.DeployFlags.IntoNamespace,
+			MapNamespaces:  o.DeployFlags.MapNamespaces,
+			ResourceFilter: &resourceFilter,
+			BoolFilter:     boolFilter,
+		},
+	}
+}
+
+// recordedPreparation prepares recorded resources the same way as they were originally prepared
+func (o *DeployOptions) recordedPreparation(resourceTypes ctlres.ResourceTypes, opts ctlapp.RecordedResourcesOpts) ctlapp.Preparation {
+	prepOpts := o.DeployFlags.PrepareResourcesOpts
+	prepOpts.IntoNamespace = opts.IntoNamespace
+	prepOpts.MapNamespaces = opts.MapNamespaces
+	return ctlapp.NewPreparation(resourceTypes, prepOpts)
+}
+
+func recordedResourceFilter(opts ctlapp.RecordedResourcesOpts) ctlres.ResourceFilter {
+	var resourceFilter ctlres.ResourceFilter
+	if opts.ResourceFilter != nil {
+		resourceFilter = *opts.ResourceFilter
+	}
+	resourceFilter.BoolFilter = opts.BoolFilter
+	return resourceFilter
+}
@@ -0,0 +1,28 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	"github.com/spf13/cobra"
+)
+
+type RiskFlags struct {
+	FailOnRisk string
+}
+
+func (s *RiskFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().Var(cmdcore.NewOneOfFlag(&s.FailOnRisk, "risk level", ctlconf.RiskLevels), "fail-on-risk",
+		fmt.Sprintf("Fail if any change is classified with given risk level or higher (one of: %s)",
+			strings.Join(ctlconf.RiskLevels, ", ")))
+}
+
+func (s *RiskFlags) RiskCheck(conf ctlconf.Conf) ctlcap.RiskCheck {
+	return ctlcap.RiskCheck{Classifier: ctlcap.NewRiskClassifier(conf.RiskRules()), FailOn: s.FailOnRisk}
+}
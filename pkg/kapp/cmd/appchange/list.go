@@ -98,6 +98,9 @@ func (t AppChangesTable) Print(ui ui.UI) {
 	nsHeader := uitable.NewHeader("Namespaces")
 	nsHeader.Hidden = true
 
+	failureReasonHeader := uitable.NewHeader("Failure Reason")
+	failureReasonHeader.Hidden = !t.anyFailureReason()
+
 	table := uitable.Table{
 		Title:   t.Title,
 		Content: "app changes",
@@ -108,6 +111,7 @@ func (t AppChangesTable) Print(ui ui.UI) {
 			uitable.NewHeader("Finished At"),
 			uitable.NewHeader("Successful"),
 			uitable.NewHeader("Description"),
+			failureReasonHeader,
 			nsHeader,
 		},
 
@@ -132,9 +136,19 @@ func (t AppChangesTable) Print(ui ui.UI) {
 				Error: change.Meta().Successful == nil || *change.Meta().Successful != true,
 			},
 			uitable.NewValueString(change.Meta().Description),
+			uitable.NewValueString(change.Meta().FailureReason),
 			uitable.NewValueString(strings.Join(change.Meta().Namespaces, ",")),
 		})
 	}
 
 	ui.PrintTable(table)
 }
+
+func (t AppChangesTable) anyFailureReason() bool {
+	for _, change := range t.Changes {
+		if len(change.Meta().FailureReason) > 0 {
+			return true
+		}
+	}
+	return false
+}
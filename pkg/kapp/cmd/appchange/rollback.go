@@ -0,0 +1,103 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package appchange
+
+import (
+	"fmt"
+
+	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+	cmdapp "carvel.dev/kapp/pkg/kapp/cmd/app"
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	"carvel.dev/kapp/pkg/kapp/logger"
+	"carvel.dev/kapp/pkg/kapp/preflight"
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+)
+
+type RollbackOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	DeployOptions *cmdapp.DeployOptions
+	ChangeName    string
+}
+
+func NewRollbackOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger, preflights *preflight.Registry) *RollbackOptions {
+	return &RollbackOptions{ui: ui, depsFactory: depsFactory, logger: logger,
+		DeployOptions: cmdapp.NewDeployOptions(ui, depsFactory, logger, preflights)}
+}
+
+func NewRollbackCmd(o *RollbackOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Rollback app to resources recorded in an app change",
+		RunE:  func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Annotations: map[string]string{
+			cmdapp.TTYByDefaultKey: "",
+		},
+		Example: `
+  # Rollback app 'app1' to app change 'app1-change-abcde'
+  kapp app-change rollback -a app1 --change app1-change-abcde`,
+	}
+
+	deployOpts := o.DeployOptions
+
+	deployOpts.AppFlags.Set(cmd, flagsFactory)
+	deployOpts.DiffFlags.SetWithPrefix("diff", cmd)
+	deployOpts.ApplyFlags.SetWithDefaults("", cmdapp.ApplyFlagsDeployDefaults, cmd)
+	deployOpts.DeployFlags.Set(cmd)
+	deployOpts.ResourceTypesFlags.Set(cmd)
+	deployOpts.PreflightChecks.AddFlags(cmd.Flags())
+
+	cmd.Flags().StringVar(&o.ChangeName, "change", "", "Set app change name to rollback to")
+
+	return cmd
+}
+
+func (o *RollbackOptions) Run() error {
+	if len(o.ChangeName) == 0 {
+		return fmt.Errorf("Expected app change name to be specified via --change")
+	}
+
+	app, _, err := cmdapp.Factory(o.depsFactory, o.DeployOptions.AppFlags, cmdapp.ResourceTypesFlags{}, o.logger)
+	if err != nil {
+		return err
+	}
+
+	changes, err := app.Changes()
+	if err != nil {
+		return err
+	}
+
+	var change ctlapp.Change
+
+	for _, c := range changes {
+		if c.Name() == o.ChangeName {
+			change = c
+			break
+		}
+	}
+
+	if change == nil {
+		return fmt.Errorf("Expected to find app change '%s' for %s", o.ChangeName, app.Description())
+	}
+
+	recorded, found, err := change.Resources()
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("Expected app change '%s' to have recorded resources "+
+			"(hint: resources are not recorded for deletes, very large apps or by older kapp versions)", o.ChangeName)
+	}
+
+	o.ui.PrintLinef("Rolling back %s to app change '%s' (%s)",
+		app.Description(), change.Name(), change.Meta().Description)
+
+	o.DeployOptions.UseRecordedResources(recorded)
+
+	return o.DeployOptions.Run()
+}
@@ -0,0 +1,45 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package appgroup
+
+import (
+	"errors"
+	"io/fs"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+)
+
+const (
+	appConfigFileName   = "kapp-app.yml"
+	appConfigAPIVersion = "kapp.k14s.io/v1alpha1"
+	appConfigKind       = "AppGroupApp"
+)
+
+// AppConfig is an optional configuration file placed in an app directory.
+// It is not deployed as part of the app.
+type AppConfig struct {
+	cmdcore.ConfigFileHeader
+
+	// DependsOn lists other apps (specified via their directory names)
+	// within the same group that need to be deployed before this app
+	DependsOn []string `json:"dependsOn"`
+}
+
+func NewAppConfigFromFile(path string) (AppConfig, bool, error) {
+	var config AppConfig
+
+	err := cmdcore.ConfigFile{Path: path, Description: "app config"}.Read(&config)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return AppConfig{}, false, nil
+		}
+		return AppConfig{}, false, err
+	}
+
+	return config, true, nil
+}
+
+func (c AppConfig) Validate() error {
+	return c.ConfigFileHeader.Validate(appConfigAPIVersion, appConfigKind)
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package appgroup
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+)
+
+const (
+	appGroupConfigKind = "AppGroup"
+)
+
+// AppGroupConfig declares apps of an app group together with their
+// deploy settings, as an alternative to one directory per app layout
+type AppGroupConfig struct {
+	cmdcore.ConfigFileHeader
+
+	Apps []AppGroupConfigApp `json:"apps"`
+}
+
+type AppGroupConfigApp struct {
+	// Name is appended to group name to form app name
+	Name string `json:"name"`
+	// Path is a file or a directory with app configuration
+	// (relative paths are resolved against AppGroup file location)
+	Path string `json:"path"`
+
+	// Namespace is used for app resources that do not specify
+	// namespace (defaults to group's namespace). App itself
+	// is kept in group's app namespace.
+	Namespace string `json:"namespace,omitempty"`
+	// IntoNamespace places all app resources into namespace
+	IntoNamespace string `json:"intoNamespace,omitempty"`
+	// Labels are added to app (format: key=val)
+	Labels []string `json:"labels,omitempty"`
+
+	// ApplyTimeout and WaitTimeout override group's timeouts (format: 15m)
+	ApplyTimeout string `json:"applyTimeout,omitempty"`
+	WaitTimeout  string `json:"waitTimeout,omitempty"`
+
+	// DependsOn lists names of other apps within the group
+	// that need to be deployed before this app
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+func NewAppGroupConfigFromFile(path string) (AppGroupConfig, error) {
+	var config AppGroupConfig
+
+	err := cmdcore.ConfigFile{Path: path, Description: "app group config"}.Read(&config)
+	if err != nil {
+		return AppGroupConfig{}, err
+	}
+
+	return config, nil
+}
+
+func (c AppGroupConfig) Validate() error {
+	err := c.ConfigFileHeader.Validate(appConfigAPIVersion, appGroupConfigKind)
+	if err != nil {
+		return err
+	}
+
+	names := map[string]struct{}{}
+
+	for i, app := range c.Apps {
+		err := app.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating app %d: %w", i, err)
+		}
+		if _, found := names[app.Name]; found {
+			return fmt.Errorf("Validating app %d: Expected name '%s' to be unique", i, app.Name)
+		}
+		names[app.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+func (c AppGroupConfigApp) Validate() error {
+	if len(c.Name) == 0 {
+		return fmt.Errorf("Expected name to be non-empty")
+	}
+	if len(c.Path) == 0 {
+		return fmt.Errorf("Expected path to be non-empty")
+	}
+
+	for _, label := range c.Labels {
+		if !strings.Contains(label, "=") {
+			return fmt.Errorf("Expected label '%s' to be in 'key=val' format", label)
+		}
+	}
+
+	_, err := c.ApplyTimeoutDuration()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.WaitTimeoutDuration()
+	return err
+}
+
+// ApplyTimeoutDuration returns zero if timeout is not specified
+func (c AppGroupConfigApp) ApplyTimeoutDuration() (time.Duration, error) {
+	return parseOptionalDuration("applyTimeout", c.ApplyTimeout)
+}
+
+// WaitTimeoutDuration returns zero if timeout is not specified
+func (c AppGroupConfigApp) WaitTimeoutDuration() (time.Duration, error) {
+	return parseOptionalDuration("waitTimeout", c.WaitTimeout)
+}
+
+func parseOptionalDuration(field, val string) (time.Duration, error) {
+	if len(val) == 0 {
+		return 0, nil
+	}
+	dur, err := time.ParseDuration(val)
+	if err != nil {
+		return 0, fmt.Errorf("Parsing %s: %w", field, err)
+	}
+	return dur, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package appgroup
+
+import (
+	"fmt"
+	"strings"
+
+	ctlapp "carvel.dev/kapp/pkg/kapp/app"
+)
+
+// sortAppNamesByDeps orders apps so that each app comes after apps it depends on.
+// Relative order of independent apps is preserved. Dependencies on apps
+// that are not included in names are ignored.
+func sortAppNamesByDeps(names []string, deps map[string][]string) ([]string, error) {
+	included := map[string]struct{}{}
+	for _, name := range names {
+		included[name] = struct{}{}
+	}
+
+	var sorted []string
+	sortedNames := map[string]struct{}{}
+
+	for len(sorted) < len(names) {
+		var progressed bool
+
+		for _, name := range names {
+			if _, found := sortedNames[name]; found {
+				continue
+			}
+			if !depsSatisfied(deps[name], included, sortedNames) {
+				continue
+			}
+			sorted = append(sorted, name)
+			sortedNames[name] = struct{}{}
+			progressed = true
+		}
+
+		if !progressed {
+			var cycledNames []string
+			for _, name := range names {
+				if _, found := sortedNames[name]; !found {
+					cycledNames = append(cycledNames, name)
+				}
+			}
+			return nil, fmt.Errorf("Expected apps to not have cyclic dependencies, but found cycle between apps: %s",
+				strings.Join(cycledNames, ", "))
+		}
+	}
+
+	return sorted, nil
+}
+
+func depsSatisfied(deps []string, included, sortedNames map[string]struct{}) bool {
+	for _, dep := range deps {
+		if _, found := included[dep]; !found {
+			continue
+		}
+		if _, found := sortedNames[dep]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+// appsInDeleteOrder orders apps so that each app is deleted
+// before apps it depends on (based on dependencies recorded during deploy)
+func appsInDeleteOrder(apps []ctlapp.App) ([]ctlapp.App, error) {
+	var names []string
+	appsByName := map[string]ctlapp.App{}
+	deps := map[string][]string{}
+
+	for _, app := range apps {
+		meta, err := app.Meta()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, app.Name())
+		appsByName[app.Name()] = app
+		deps[app.Name()] = meta.DependsOn
+	}
+
+	sortedNames, err := sortAppNamesByDeps(names, deps)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []ctlapp.App
+	for i := len(sortedNames) - 1; i >= 0; i-- {
+		result = append(result, appsByName[sortedNames[i]])
+	}
+	return result, nil
+}
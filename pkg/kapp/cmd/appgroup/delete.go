@@ -31,6 +31,9 @@ type DeleteOptions struct {
 type DeleteAppFlags struct {
 	DiffFlags  cmdtools.DiffFlags
 	ApplyFlags cmdapp.ApplyFlags
+
+	DeletionProtectionFlags cmdapp.DeletionProtectionFlags
+	RiskFlags               cmdapp.RiskFlags
 }
 
 func NewDeleteOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *DeleteOptions {
@@ -47,6 +50,8 @@ func NewDeleteCmd(o *DeleteOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 	o.AppGroupFlags.Set(cmd, flagsFactory)
 	o.AppFlags.DiffFlags.SetWithPrefix("diff", cmd)
 	o.AppFlags.ApplyFlags.SetWithDefaults("", cmdapp.ApplyFlagsDeleteDefaults, cmd)
+	o.AppFlags.DeletionProtectionFlags.Set(cmd)
+	o.AppFlags.RiskFlags.Set(cmd)
 	return cmd
 }
 
@@ -65,6 +70,11 @@ func (o *DeleteOptions) Run() error {
 		return err
 	}
 
+	appsInGroup, err = appsInDeleteOrder(appsInGroup)
+	if err != nil {
+		return err
+	}
+
 	for _, app := range appsInGroup {
 		err := o.deleteApp(app.Name())
 		if err != nil {
@@ -87,6 +97,8 @@ func (o *DeleteOptions) deleteApp(name string) error {
 	}
 	deleteOpts.DiffFlags = o.AppFlags.DiffFlags
 	deleteOpts.ApplyFlags = o.AppFlags.ApplyFlags
+	deleteOpts.DeletionProtectionFlags = o.AppFlags.DeletionProtectionFlags
+	deleteOpts.RiskFlags = o.AppFlags.RiskFlags
 
 	return deleteOpts.Run()
 }
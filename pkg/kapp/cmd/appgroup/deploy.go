@@ -4,11 +4,15 @@
 package appgroup
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
+	ctlapp "carvel.dev/kapp/pkg/kapp/app"
 	cmdapp "carvel.dev/kapp/pkg/kapp/cmd/app"
 	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
 	cmdtools "carvel.dev/kapp/pkg/kapp/cmd/tools"
@@ -36,6 +40,10 @@ type DeployAppFlags struct {
 	DeleteApplyFlags    cmdapp.ApplyFlags
 	DeployFlags         cmdapp.DeployFlags
 	LabelFlags          cmdapp.LabelFlags
+	LockFlags           cmdapp.LockFlags
+
+	DeletionProtectionFlags cmdapp.DeletionProtectionFlags
+	RiskFlags               cmdapp.RiskFlags
 }
 
 func NewDeployOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger, preflights *preflight.Registry) *DeployOptions {
@@ -49,6 +57,30 @@ func NewDeployCmd(o *DeployOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 		Short:       "Deploy app group",
 		RunE:        func(_ *cobra.Command, _ []string) error { return o.Run() },
 		Annotations: map[string]string{cmdapp.TTYByDefaultKey: ""},
+		Example: `
+  # Deploy each sub-directory of config/ as an app in group 'group1'
+  kapp app-group deploy -g group1 --directory config/
+
+  # Apps are deployed after apps they depend on (and deleted before them)
+  # when their directory includes kapp-app.yml file, for example:
+  #   apiVersion: kapp.k14s.io/v1alpha1
+  #   kind: AppGroupApp
+  #   dependsOn: [infra]
+
+  # Deploy apps listed in AppGroup file with per-app settings, for example:
+  #   apiVersion: kapp.k14s.io/v1alpha1
+  #   kind: AppGroup
+  #   apps:
+  #   - name: infra
+  #     path: infra/
+  #     namespace: infra
+  #     labels: [team=platform]
+  #     waitTimeout: 30m
+  #   - name: web
+  #     path: web.yml
+  #     intoNamespace: web
+  #     dependsOn: [infra]
+  kapp app-group deploy -g group1 -f group.yml`,
 	}
 	o.AppGroupFlags.Set(cmd, flagsFactory)
 	o.DeployFlags.Set(cmd)
@@ -58,6 +90,9 @@ func NewDeployCmd(o *DeployOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 	o.AppFlags.DeleteApplyFlags.SetWithDefaults("delete", cmdapp.ApplyFlagsDeleteDefaults, cmd)
 	o.AppFlags.DeployFlags.Set(cmd)
 	o.AppFlags.LabelFlags.Set(cmd)
+	o.AppFlags.LockFlags.Set(cmd)
+	o.AppFlags.DeletionProtectionFlags.Set(cmd)
+	o.AppFlags.RiskFlags.Set(cmd)
 	o.PreflightChecks.AddFlags(cmd.Flags())
 	return cmd
 }
@@ -67,6 +102,15 @@ func (o *DeployOptions) Run() error {
 		return fmt.Errorf("Expected group name to be non-empty")
 	}
 
+	if o.DeployFlags.AppConcurrency < 1 {
+		return fmt.Errorf("Expected --app-concurrency to be greater than 0")
+	}
+
+	// Confirmation prompts of concurrently deployed apps would interleave
+	if o.DeployFlags.AppConcurrency > 1 && o.ui.IsInteractive() && !o.AppFlags.DiffFlags.Run {
+		return fmt.Errorf("Expected --yes (-y) to be specified when deploying apps concurrently")
+	}
+
 	// TODO what if app is renamed? currently it
 	// will have conflicting resources with new-named app
 	updatedApps, err := o.appsToUpdate()
@@ -74,20 +118,12 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
-	var exitCode float64
-	// TODO is there some order between apps?
-	for _, appGroupApp := range updatedApps {
-		err := o.deployApp(appGroupApp)
-		if err != nil {
-			if deployErr, ok := err.(cmdapp.DeployDiffExitStatus); ok {
-				exitCode = math.Max(exitCode, float64(deployErr.ExitStatus()))
-			} else {
-				return err
-			}
-		}
+	supportObjs, err := cmdapp.FactoryClients(o.depsFactory, o.AppGroupFlags.NamespaceFlags, o.AppGroupFlags.AppNamespace, cmdapp.ResourceTypesFlags{}, o.logger)
+	if err != nil {
+		return err
 	}
 
-	supportObjs, err := cmdapp.FactoryClients(o.depsFactory, o.AppGroupFlags.NamespaceFlags, o.AppGroupFlags.AppNamespace, cmdapp.ResourceTypesFlags{}, o.logger)
+	exitCode, err := o.deployApps(updatedApps, supportObjs.Apps)
 	if err != nil {
 		return err
 	}
@@ -97,6 +133,8 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
+	var appsToDelete []ctlapp.App
+
 	// Delete apps that no longer are present in directories
 	for _, app := range existingAppsInGroup {
 		var found bool
@@ -107,10 +145,19 @@ func (o *DeployOptions) Run() error {
 			}
 		}
 		if !found {
-			err := o.deleteApp(app.Name())
-			if err != nil {
-				return err
-			}
+			appsToDelete = append(appsToDelete, app)
+		}
+	}
+
+	appsToDelete, err = appsInDeleteOrder(appsToDelete)
+	if err != nil {
+		return err
+	}
+
+	for _, app := range appsToDelete {
+		err := o.deleteApp(app.Name())
+		if err != nil {
+			return err
 		}
 	}
 
@@ -125,9 +172,30 @@ func (o *DeployOptions) Run() error {
 type appGroupApp struct {
 	Name string
 	Path string
+	// Files excludes app config file
+	Files     []string
+	DependsOn []string
+
+	// Following settings override group's flags if non-empty
+	Namespace     string
+	IntoNamespace string
+	Labels        []string
+	ApplyTimeout  time.Duration
+	WaitTimeout   time.Duration
 }
 
 func (o *DeployOptions) appsToUpdate() ([]appGroupApp, error) {
+	switch {
+	case len(o.DeployFlags.Directory) > 0 && len(o.DeployFlags.File) > 0:
+		return nil, fmt.Errorf("Expected only one of --directory (-d) or --file (-f) to be specified")
+	case len(o.DeployFlags.File) > 0:
+		return o.appsToUpdateFromFile()
+	default:
+		return o.appsToUpdateFromDirectory()
+	}
+}
+
+func (o *DeployOptions) appsToUpdateFromDirectory() ([]appGroupApp, error) {
 	var applications []appGroupApp
 
 	dir := o.DeployFlags.Directory
@@ -141,39 +209,272 @@ func (o *DeployOptions) appsToUpdate() ([]appGroupApp, error) {
 		if !fi.IsDir() {
 			continue
 		}
-		app := appGroupApp{
-			Name: fmt.Sprintf("%s-%s", o.AppGroupFlags.Name, fi.Name()),
-			Path: filepath.Join(dir, fi.Name()),
+		app, err := o.appToUpdate(o.appName(fi.Name()), filepath.Join(dir, fi.Name()))
+		if err != nil {
+			return nil, err
 		}
 		applications = append(applications, app)
 	}
-	return applications, nil
+
+	return o.sortApps(applications)
+}
+
+func (o *DeployOptions) appsToUpdateFromFile() ([]appGroupApp, error) {
+	var applications []appGroupApp
+
+	config, err := NewAppGroupConfigFromFile(o.DeployFlags.File)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, configApp := range config.Apps {
+		path := configApp.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(o.DeployFlags.File), path)
+		}
+
+		app, err := o.appToUpdate(o.appName(configApp.Name), path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dep := range configApp.DependsOn {
+			app.DependsOn = append(app.DependsOn, o.appName(dep))
+		}
+
+		app.Namespace = configApp.Namespace
+		app.IntoNamespace = configApp.IntoNamespace
+		app.Labels = configApp.Labels
+
+		// Errors are checked during config validation
+		app.ApplyTimeout, _ = configApp.ApplyTimeoutDuration()
+		app.WaitTimeout, _ = configApp.WaitTimeoutDuration()
+
+		applications = append(applications, app)
+	}
+
+	return o.sortApps(applications)
+}
+
+func (o *DeployOptions) appToUpdate(name, path string) (appGroupApp, error) {
+	app := appGroupApp{Name: name, Path: path}
+
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return appGroupApp{}, fmt.Errorf("Checking app path '%s': %w", path, err)
+	}
+
+	if !fileInfo.IsDir() {
+		app.Files = []string{path}
+		return app, nil
+	}
+
+	config, found, err := NewAppConfigFromFile(filepath.Join(path, appConfigFileName))
+	if err != nil {
+		return appGroupApp{}, err
+	}
+
+	if !found {
+		app.Files = []string{path}
+		return app, nil
+	}
+
+	for _, dep := range config.DependsOn {
+		app.DependsOn = append(app.DependsOn, o.appName(dep))
+	}
+
+	fileInfos, err := os.ReadDir(path)
+	if err != nil {
+		return appGroupApp{}, fmt.Errorf("Reading directory '%s': %w", path, err)
+	}
+
+	for _, fi := range fileInfos {
+		if fi.Name() != appConfigFileName {
+			app.Files = append(app.Files, filepath.Join(path, fi.Name()))
+		}
+	}
+
+	return app, nil
 }
 
-func (o *DeployOptions) deployApp(app appGroupApp) error {
-	o.ui.PrintLinef("--- deploying app '%s' (namespace: %s) from %s",
+// sortApps orders apps so that dependencies are deployed first
+func (o *DeployOptions) sortApps(apps []appGroupApp) ([]appGroupApp, error) {
+	var names []string
+	appsByName := map[string]appGroupApp{}
+	deps := map[string][]string{}
+
+	for _, app := range apps {
+		names = append(names, app.Name)
+		appsByName[app.Name] = app
+		deps[app.Name] = app.DependsOn
+	}
+
+	for _, app := range apps {
+		for _, dep := range app.DependsOn {
+			if _, found := appsByName[dep]; !found {
+				return nil, fmt.Errorf("Expected app '%s' dependency '%s' to be an app within the group", app.Name, dep)
+			}
+		}
+	}
+
+	sortedNames, err := sortAppNamesByDeps(names, deps)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []appGroupApp
+	for _, name := range sortedNames {
+		result = append(result, appsByName[name])
+	}
+	return result, nil
+}
+
+func (o *DeployOptions) appName(dirName string) string {
+	return fmt.Sprintf("%s-%s", o.AppGroupFlags.Name, dirName)
+}
+
+type appDeployResult struct {
+	App appGroupApp
+	Err error
+}
+
+// deployApps deploys each app once all of its dependencies are deployed,
+// running up to configured number of deploys concurrently. Failures do not stop
+// deploys of apps that are independent of failed apps, and are returned together.
+func (o *DeployOptions) deployApps(apps []appGroupApp, recordedApps ctlapp.Apps) (float64, error) {
+	var outputLock sync.Mutex
+	var exitCode float64
+	var errs []error
+
+	// Maps app name to result of its deploy (nil if successful)
+	finished := map[string]error{}
+	resultsCh := make(chan appDeployResult, len(apps))
+	pending := apps
+	running := 0
+
+	for len(pending) > 0 || running > 0 {
+		var stillPending []appGroupApp
+
+		for _, app := range pending {
+			ready, failedDep := o.depsFinished(app, finished)
+			switch {
+			case len(failedDep) > 0:
+				err := fmt.Errorf("Skipped deploying app '%s' since its dependency '%s' failed", app.Name, failedDep)
+				finished[app.Name] = err
+				errs = append(errs, err)
+
+			case ready && running < o.DeployFlags.AppConcurrency:
+				appUI := o.ui
+				if o.DeployFlags.AppConcurrency > 1 {
+					appUI = cmdcore.NewPrefixedUI(o.ui, fmt.Sprintf("%s | ", app.Name), &outputLock)
+				}
+				running++
+				go func(app appGroupApp) {
+					resultsCh <- appDeployResult{app, o.deployApp(app, appUI, recordedApps)}
+				}(app)
+
+			default:
+				stillPending = append(stillPending, app)
+			}
+		}
+
+		pending = stillPending
+
+		if running == 0 {
+			break
+		}
+
+		result := <-resultsCh
+		running--
+
+		if deployErr, ok := result.Err.(cmdapp.DeployDiffExitStatus); ok {
+			exitCode = math.Max(exitCode, float64(deployErr.ExitStatus()))
+			result.Err = nil
+		}
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("Deploying app '%s': %w", result.App.Name, result.Err))
+		}
+		finished[result.App.Name] = result.Err
+	}
+
+	return exitCode, errors.Join(errs...)
+}
+
+// depsFinished returns true if all app dependencies were successfully deployed;
+// or returns name of the first failed dependency
+func (o *DeployOptions) depsFinished(app appGroupApp, finished map[string]error) (bool, string) {
+	for _, dep := range app.DependsOn {
+		err, found := finished[dep]
+		if !found {
+			return false, ""
+		}
+		if err != nil {
+			return false, dep
+		}
+	}
+	return true, ""
+}
+
+func (o *DeployOptions) deployApp(app appGroupApp, ui ui.UI, recordedApps ctlapp.Apps) error {
+	ui.PrintLinef("--- deploying app '%s' (namespace: %s) from %s",
 		app.Name, o.appNamespace(), app.Path)
 
-	deployOpts := cmdapp.NewDeployOptions(o.ui, o.depsFactory, o.logger, o.PreflightChecks)
+	deployOpts := cmdapp.NewDeployOptions(ui, o.depsFactory, o.logger, o.PreflightChecks)
 	deployOpts.AppFlags = cmdapp.Flags{
 		Name:           app.Name,
 		NamespaceFlags: o.AppGroupFlags.NamespaceFlags,
 		AppNamespace:   o.AppGroupFlags.AppNamespace,
 	}
+	if len(app.Namespace) > 0 {
+		// Keep app in group's namespace so that it's found with the rest of the group
+		deployOpts.AppFlags.NamespaceFlags = cmdcore.NamespaceFlags{Name: app.Namespace}
+		deployOpts.AppFlags.AppNamespace = o.appNamespace()
+	}
 	deployOpts.FileFlags = cmdtools.FileFlags{
-		Files: []string{app.Path},
+		Files: app.Files,
 	}
 	deployOpts.DiffFlags = o.AppFlags.DiffFlags
 	deployOpts.ResourceFilterFlags = o.AppFlags.ResourceFilterFlags
 	deployOpts.ApplyFlags = o.AppFlags.ApplyFlags
 	deployOpts.DeployFlags = o.AppFlags.DeployFlags
+	deployOpts.LockFlags = o.AppFlags.LockFlags
+	deployOpts.DeletionProtectionFlags = o.AppFlags.DeletionProtectionFlags
+	deployOpts.RiskFlags = o.AppFlags.RiskFlags
+
+	if len(app.IntoNamespace) > 0 {
+		deployOpts.DeployFlags.IntoNamespace = app.IntoNamespace
+	}
+	if app.ApplyTimeout > 0 {
+		deployOpts.ApplyFlags.ApplyingChangesOpts.Timeout = app.ApplyTimeout
+	}
+	if app.WaitTimeout > 0 {
+		deployOpts.ApplyFlags.WaitingChangesOpts.Timeout = app.WaitTimeout
+	}
 
-	deployOpts.LabelFlags = o.AppFlags.LabelFlags
+	// Copy labels to avoid sharing backing array between concurrently deployed apps
+	deployOpts.LabelFlags.Labels = append([]string{}, o.AppFlags.LabelFlags.Labels...)
+	deployOpts.LabelFlags.Labels = append(deployOpts.LabelFlags.Labels, app.Labels...)
 	deployOpts.LabelFlags.Labels = append(
 		deployOpts.LabelFlags.Labels,
 		fmt.Sprintf("%s=%s", appGroupAnnKey, o.AppGroupFlags.Name))
 
-	return deployOpts.Run()
+	err := deployOpts.Run()
+	if err != nil {
+		return err
+	}
+
+	// App is not created during diff runs
+	if o.AppFlags.DiffFlags.Run || len(o.AppFlags.DeployFlags.PlanOutput) > 0 {
+		return nil
+	}
+
+	// Record dependencies so that apps could be deleted in reverse order
+	recordedApp, err := recordedApps.Find(app.Name)
+	if err != nil {
+		return err
+	}
+
+	return recordedApp.UpdateDependsOn(app.DependsOn)
 }
 
 func (o *DeployOptions) deleteApp(name string) error {
@@ -188,6 +489,9 @@ func (o *DeployOptions) deleteApp(name string) error {
 	}
 	deleteOpts.DiffFlags = o.AppFlags.DiffFlags
 	deleteOpts.ApplyFlags = o.AppFlags.DeleteApplyFlags
+	deleteOpts.LockFlags = o.AppFlags.LockFlags
+	deleteOpts.DeletionProtectionFlags = o.AppFlags.DeletionProtectionFlags
+	deleteOpts.RiskFlags = o.AppFlags.RiskFlags
 
 	return deleteOpts.Run()
 }
@@ -8,9 +8,14 @@ import (
 )
 
 type DeployFlags struct {
-	Directory string
+	Directory      string
+	File           string
+	AppConcurrency int
 }
 
 func (s *DeployFlags) Set(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&s.Directory, "directory", "d", "", "Set directory (format: /tmp/foo)")
+	cmd.Flags().StringVarP(&s.File, "file", "f", "", "Set AppGroup file listing apps and their settings (format: /tmp/group.yml)")
+	cmd.Flags().IntVar(&s.AppConcurrency, "app-concurrency", 1,
+		"Maximum number of apps to deploy concurrently (apps are deployed after apps they depend on)")
 }
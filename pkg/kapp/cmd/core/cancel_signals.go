@@ -6,19 +6,28 @@ package core
 import (
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 )
 
 type CancelSignals struct{}
 
-func (CancelSignals) Watch(stopFunc func()) {
+// Watch calls stopFunc once when interrupt or hangup signal is received.
+// Subsequent signals are handled by default Go handlers (i.e. terminate process).
+// Returned function stops watching for signals.
+func (CancelSignals) Watch(stopFunc func()) func() {
 	signalCh := make(chan os.Signal, 1)
+	doneCh := make(chan struct{})
 	signal.Notify(signalCh, syscall.SIGINT, syscall.SIGHUP)
 	go func() {
 		defer signal.Stop(signalCh)
 		select {
 		case <-signalCh:
 			stopFunc()
+		case <-doneCh:
 		}
 	}()
+
+	var doneOnce sync.Once
+	return func() { doneOnce.Do(func() { close(doneCh) }) }
 }
@@ -0,0 +1,33 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"github.com/cppforlife/go-cli-ui/ui"
+)
+
+// JSONUI is implemented by UIs that know whether output is rendered as JSON
+type JSONUI interface {
+	IsJSON() bool
+}
+
+// ConfUI remembers whether JSON output was enabled so that
+// wrapping UIs (e.g. PrefixedUI) could keep output structured
+type ConfUI struct {
+	*ui.ConfUI
+	json bool
+}
+
+var _ JSONUI = &ConfUI{}
+
+func NewConfUI(parent *ui.ConfUI) *ConfUI {
+	return &ConfUI{ConfUI: parent}
+}
+
+func (ui *ConfUI) EnableJSON() {
+	ui.ConfUI.EnableJSON()
+	ui.json = true
+}
+
+func (ui *ConfUI) IsJSON() bool { return ui.json }
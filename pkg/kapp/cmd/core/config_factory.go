@@ -20,6 +20,13 @@ type ConfigFactory interface {
 	ConfigureClient(float32, int)
 	RESTConfig() (*rest.Config, error)
 	DefaultNamespace() (string, error)
+
+	// KubeconfigContexts returns configured contexts
+	// (multiple contexts are separated by comma, e.g. "a,b")
+	KubeconfigContexts() ([]string, error)
+	// WithKubeconfig returns config factory that targets given context
+	// (and optionally kubeconfig path) while keeping other settings
+	WithKubeconfig(path, context string) ConfigFactory
 }
 
 type ConfigFactoryImpl struct {
@@ -54,7 +61,44 @@ func (f *ConfigFactoryImpl) ConfigureClient(qps float32, burst int) {
 	f.burst = burst
 }
 
+func (f *ConfigFactoryImpl) KubeconfigContexts() ([]string, error) {
+	context, err := f.contextResolverFunc()
+	if err != nil {
+		return nil, fmt.Errorf("Resolving config context: %w", err)
+	}
+
+	var contexts []string
+
+	for _, name := range strings.Split(context, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) > 0 {
+			contexts = append(contexts, name)
+		}
+	}
+
+	return contexts, nil
+}
+
+func (f *ConfigFactoryImpl) WithKubeconfig(path, context string) ConfigFactory {
+	result := *f
+	if len(path) > 0 {
+		result.pathResolverFunc = func() (string, error) { return path, nil }
+	}
+	result.contextResolverFunc = func() (string, error) { return context, nil }
+	return &result
+}
+
 func (f *ConfigFactoryImpl) RESTConfig() (*rest.Config, error) {
+	contexts, err := f.KubeconfigContexts()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(contexts) > 1 {
+		return nil, fmt.Errorf("Expected single kubeconfig context, but was given %d "+
+			"(hint: multiple contexts are only supported by deploy command)", len(contexts))
+	}
+
 	isExplicitYAMLConfig, config, err := f.clientConfig()
 	if err != nil {
 		return nil, err
@@ -102,9 +146,15 @@ func (f *ConfigFactoryImpl) clientConfig() (bool, clientcmd.ClientConfig, error)
 		return false, nil, fmt.Errorf("Resolving config path: %w", err)
 	}
 
-	context, err := f.contextResolverFunc()
+	// Default namespace is taken from the first context when multiple are given
+	var context string
+
+	contexts, err := f.KubeconfigContexts()
 	if err != nil {
-		return false, nil, fmt.Errorf("Resolving config context: %w", err)
+		return false, nil, err
+	}
+	if len(contexts) > 0 {
+		context = contexts[0]
 	}
 
 	configYAML, err := f.yamlResolverFunc()
@@ -0,0 +1,54 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+// ConfigFileHeader identifies type of configuration file
+// (meant to be embedded into configuration structs)
+type ConfigFileHeader struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+}
+
+func (h ConfigFileHeader) Validate(apiVersion, kind string) error {
+	if h.APIVersion != apiVersion {
+		return fmt.Errorf("Validating apiVersion: Unknown version (known: %s)", apiVersion)
+	}
+	if h.Kind != kind {
+		return fmt.Errorf("Validating kind: Unknown kind (known: %s)", kind)
+	}
+	return nil
+}
+
+// ConfigFile reads YAML configuration files (e.g. app group config).
+// Description is used to identify file in errors.
+type ConfigFile struct {
+	Path        string
+	Description string
+}
+
+func (f ConfigFile) Read(config interface{ Validate() error }) error {
+	bs, err := os.ReadFile(f.Path)
+	if err != nil {
+		return fmt.Errorf("Reading %s '%s': %w", f.Description, f.Path, err)
+	}
+
+	err = yaml.Unmarshal(bs, config)
+	if err != nil {
+		return fmt.Errorf("Unmarshaling %s '%s': %w", f.Description, f.Path, err)
+	}
+
+	err = config.Validate()
+	if err != nil {
+		return fmt.Errorf("Validating %s '%s': %w", f.Description, f.Path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	cmdcore.ConfigFileHeader
+
+	Names []string `json:"names"`
+}
+
+func (c testConfig) Validate() error {
+	return c.ConfigFileHeader.Validate("test.k14s.io/v1alpha1", "Test")
+}
+
+func TestConfigFileRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+
+	writeConfig := func(content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+
+	writeConfig(`
+apiVersion: test.k14s.io/v1alpha1
+kind: Test
+names: [a, b]
+`)
+
+	var config testConfig
+
+	err := cmdcore.ConfigFile{Path: path, Description: "test config"}.Read(&config)
+	require.NoError(t, err)
+	require.Equal(t, "Test", config.Kind)
+	require.Equal(t, []string{"a", "b"}, config.Names)
+
+	writeConfig(`
+apiVersion: test.k14s.io/v1alpha1
+kind: Other
+`)
+
+	err = cmdcore.ConfigFile{Path: path, Description: "test config"}.Read(&testConfig{})
+	require.EqualError(t, err, "Validating test config '"+path+"': Validating kind: Unknown kind (known: Test)")
+
+	err = cmdcore.ConfigFile{Path: path + "-missing", Description: "test config"}.Read(&testConfig{})
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -24,6 +24,11 @@ type DepsFactory interface {
 	CoreClient() (kubernetes.Interface, error)
 	RESTMapper() (meta.RESTMapper, error)
 	ConfigureWarnings(warnings bool)
+
+	KubeconfigContexts() ([]string, error)
+	// ForKubeconfig returns deps factory that targets given context
+	// (and optionally kubeconfig path) and reports to given UI
+	ForKubeconfig(path, context string, ui ui.UI) DepsFactory
 }
 
 type DepsFactoryImpl struct {
@@ -111,6 +116,16 @@ func (f *DepsFactoryImpl) ConfigureWarnings(warnings bool) {
 	f.Warnings = warnings
 }
 
+func (f *DepsFactoryImpl) KubeconfigContexts() ([]string, error) {
+	return f.configFactory.KubeconfigContexts()
+}
+
+func (f *DepsFactoryImpl) ForKubeconfig(path, context string, ui ui.UI) DepsFactory {
+	result := NewDepsFactoryImpl(f.configFactory.WithKubeconfig(path, context), ui)
+	result.Warnings = f.Warnings
+	return result
+}
+
 func (f *DepsFactoryImpl) printTarget(config *rest.Config) {
 	f.printTargetOnce.Do(func() {
 		nodesDesc := f.summarizeNodes(config)
@@ -0,0 +1,37 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// OneOfFlag is a string flag value restricted to a set of allowed values
+type OneOfFlag struct {
+	value   *string
+	name    string
+	allowed []string
+}
+
+var _ pflag.Value = &OneOfFlag{}
+
+// NewOneOfFlag uses name (e.g. "format") to describe value in errors
+func NewOneOfFlag(value *string, name string, allowed []string) *OneOfFlag {
+	return &OneOfFlag{value: value, name: name, allowed: allowed}
+}
+
+func (s *OneOfFlag) Set(val string) error {
+	if !slices.Contains(s.allowed, val) {
+		return fmt.Errorf("Expected %s to be one of: %s", s.name, strings.Join(s.allowed, ", "))
+	}
+	*s.value = val
+	return nil
+}
+
+func (s *OneOfFlag) Type() string   { return "string" }
+func (s *OneOfFlag) String() string { return *s.value }
@@ -0,0 +1,144 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+)
+
+// PrefixedUI prefixes each line of output so that output of
+// several concurrently running operations could be told apart.
+// UIs sharing the same lock do not interleave their lines.
+type PrefixedUI struct {
+	parent ui.UI
+	prefix string
+	lock   *sync.Mutex
+
+	begunLine string
+}
+
+var _ ui.UI = &PrefixedUI{}
+var _ JSONUI = &PrefixedUI{}
+
+func NewPrefixedUI(parent ui.UI, prefix string, lock *sync.Mutex) *PrefixedUI {
+	return &PrefixedUI{parent: parent, prefix: prefix, lock: lock}
+}
+
+func (ui *PrefixedUI) ErrorLinef(pattern string, args ...interface{}) {
+	ui.lock.Lock()
+	defer ui.lock.Unlock()
+
+	ui.parent.ErrorLinef("%s%s", ui.prefix, fmt.Sprintf(pattern, args...))
+}
+
+func (ui *PrefixedUI) PrintLinef(pattern string, args ...interface{}) {
+	ui.lock.Lock()
+	defer ui.lock.Unlock()
+
+	ui.parent.PrintLinef("%s%s", ui.prefix, fmt.Sprintf(pattern, args...))
+}
+
+// BeginLinef holds on to the beginning of a line until it's ended
+// so that other UIs cannot print in the middle of it
+func (ui *PrefixedUI) BeginLinef(pattern string, args ...interface{}) {
+	ui.lock.Lock()
+	defer ui.lock.Unlock()
+
+	ui.begunLine += fmt.Sprintf(pattern, args...)
+}
+
+func (ui *PrefixedUI) EndLinef(pattern string, args ...interface{}) {
+	ui.lock.Lock()
+	defer ui.lock.Unlock()
+
+	ui.parent.PrintLinef("%s%s%s", ui.prefix, ui.begunLine, fmt.Sprintf(pattern, args...))
+	ui.begunLine = ""
+}
+
+func (ui *PrefixedUI) PrintBlock(block []byte) {
+	ui.lock.Lock()
+	defer ui.lock.Unlock()
+
+	ui.parent.PrintBlock(ui.prefixLines(block))
+}
+
+func (ui *PrefixedUI) PrintErrorBlock(block string) {
+	ui.lock.Lock()
+	defer ui.lock.Unlock()
+
+	ui.parent.PrintErrorBlock(string(ui.prefixLines([]byte(block))))
+}
+
+// PrintTable renders table as text since tables cannot be prefixed otherwise
+// (JSON output keeps tables as is so that it could still be parsed)
+func (ui *PrefixedUI) PrintTable(table uitable.Table) {
+	if ui.IsJSON() {
+		ui.lock.Lock()
+		defer ui.lock.Unlock()
+
+		ui.parent.PrintTable(table)
+		return
+	}
+
+	var buf bytes.Buffer
+
+	newTextUI(&buf).PrintTable(table)
+
+	ui.PrintBlock(buf.Bytes())
+}
+
+func (ui *PrefixedUI) AskForText(label string) (string, error) {
+	return ui.parent.AskForText(label)
+}
+
+func (ui *PrefixedUI) AskForChoice(label string, options []string) (int, error) {
+	return ui.parent.AskForChoice(label, options)
+}
+
+func (ui *PrefixedUI) AskForPassword(label string) (string, error) {
+	return ui.parent.AskForPassword(label)
+}
+
+func (ui *PrefixedUI) AskForConfirmation() error {
+	return ui.parent.AskForConfirmation()
+}
+
+func (ui *PrefixedUI) IsInteractive() bool {
+	return ui.parent.IsInteractive()
+}
+
+func (ui *PrefixedUI) Flush() {
+	ui.parent.Flush()
+}
+
+func (ui *PrefixedUI) IsJSON() bool {
+	jsonUI, ok := ui.parent.(JSONUI)
+	return ok && jsonUI.IsJSON()
+}
+
+func (ui *PrefixedUI) prefixLines(block []byte) []byte {
+	lines := strings.SplitAfter(string(block), "\n")
+
+	var result strings.Builder
+
+	for _, line := range lines {
+		if len(line) > 0 {
+			result.WriteString(ui.prefix)
+			result.WriteString(line)
+		}
+	}
+
+	return []byte(result.String())
+}
+
+// newTextUI is declared separately since receivers above shadow ui package
+func newTextUI(buf *bytes.Buffer) ui.UI {
+	return ui.NewWriterUI(buf, buf, ui.NewNoopLogger())
+}
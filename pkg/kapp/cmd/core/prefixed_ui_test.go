@@ -0,0 +1,77 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package core_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixedUIPrefixesTextOutput(t *testing.T) {
+	var buf bytes.Buffer
+
+	prefixedUI := cmdcore.NewPrefixedUI(ui.NewWriterUI(&buf, &buf, ui.NewNoopLogger()), "app1 | ", &sync.Mutex{})
+
+	prefixedUI.PrintLinef("line %d", 1)
+	prefixedUI.BeginLinef("begun ")
+	prefixedUI.EndLinef("ended")
+	prefixedUI.PrintBlock([]byte("block1\nblock2\n"))
+	prefixedUI.PrintTable(uitable.Table{
+		Header: []uitable.Header{uitable.NewHeader("Name")},
+		Rows:   [][]uitable.Value{{uitable.NewValueString("cm1")}},
+	})
+
+	expectedOutput := "app1 | line 1\n" +
+		"app1 | begun ended\n" +
+		"app1 | block1\n" +
+		"app1 | block2\n" +
+		"app1 | Name  \n" +
+		"app1 | cm1  \n"
+
+	require.Equal(t, expectedOutput, buf.String())
+}
+
+func TestPrefixedUIKeepsJSONTables(t *testing.T) {
+	var buf bytes.Buffer
+
+	parentUI := jsonUI{ui.NewJSONUI(ui.NewWriterUI(&buf, &buf, ui.NewNoopLogger()), ui.NewNoopLogger())}
+
+	// Nested UIs are used when app group deploys apps to multiple clusters
+	prefixedUI := cmdcore.NewPrefixedUI(cmdcore.NewPrefixedUI(parentUI, "app1 | ", &sync.Mutex{}), "ctx1 | ", &sync.Mutex{})
+	require.True(t, prefixedUI.IsJSON())
+
+	prefixedUI.PrintLinef("line")
+	prefixedUI.PrintTable(uitable.Table{
+		Content: "changes",
+		Header:  []uitable.Header{uitable.NewHeader("Name")},
+		Rows:    [][]uitable.Value{{uitable.NewValueString("cm1")}},
+	})
+	parentUI.Flush()
+
+	resp := uitest.JSONUIFromBytes(t, buf.Bytes())
+
+	require.Equal(t, []string{"app1 | ctx1 | line"}, resp.Lines)
+	require.Len(t, resp.Blocks, 0)
+	require.Len(t, resp.Tables, 1)
+	require.Equal(t, "changes", resp.Tables[0].Content)
+	require.Equal(t, []map[string]string{{"name": "cm1"}}, resp.Tables[0].Rows)
+}
+
+func TestPrefixedUIIsNotJSONByDefault(t *testing.T) {
+	prefixedUI := cmdcore.NewPrefixedUI(ui.NewNoopUI(), "app1 | ", &sync.Mutex{})
+	require.False(t, prefixedUI.IsJSON())
+}
+
+type jsonUI struct {
+	*ui.JSONUI
+}
+
+func (jsonUI) IsJSON() bool { return true }
@@ -24,7 +24,7 @@ import (
 )
 
 type KappOptions struct {
-	ui            *ui.ConfUI
+	ui            *cmdcore.ConfUI
 	logger        *logger.UILogger
 	configFactory cmdcore.ConfigFactory
 	depsFactory   cmdcore.DepsFactory
@@ -42,7 +42,7 @@ type KappOptions struct {
 func NewKappOptions(ui *ui.ConfUI, configFactory cmdcore.ConfigFactory,
 	depsFactory cmdcore.DepsFactory, preflights *preflight.Registry) *KappOptions {
 
-	return &KappOptions{ui: ui, logger: logger.NewUILogger(ui),
+	return &KappOptions{ui: cmdcore.NewConfUI(ui), logger: logger.NewUILogger(ui),
 		configFactory: configFactory, depsFactory: depsFactory, PreflightChecks: preflights}
 }
 
@@ -110,6 +110,7 @@ func NewKappCmd(o *KappOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 
 	cmd.AddCommand(cmdapp.NewListCmd(cmdapp.NewListOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 	cmd.AddCommand(cmdapp.NewInspectCmd(cmdapp.NewInspectOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	cmd.AddCommand(cmdapp.NewDriftCmd(cmdapp.NewDriftOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 	cmd.AddCommand(cmdapp.NewDeployCmd(cmdapp.NewDeployOptions(o.ui, o.depsFactory, o.logger, o.PreflightChecks), flagsFactory))
 	cmd.AddCommand(cmdapp.NewDeployConfigCmd(cmdapp.NewDeployConfigOptions(o.ui, o.depsFactory), flagsFactory))
 	cmd.AddCommand(cmdapp.NewDeleteCmd(cmdapp.NewDeleteOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
@@ -117,6 +118,10 @@ func NewKappCmd(o *KappOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 	cmd.AddCommand(cmdapp.NewLogsCmd(cmdapp.NewLogsOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 	cmd.AddCommand(cmdapp.NewLabelCmd(cmdapp.NewLabelOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 
+	aCmd := cmdapp.NewCmd()
+	aCmd.AddCommand(cmdapp.NewImportHelmCmd(cmdapp.NewImportHelmOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	cmd.AddCommand(aCmd)
+
 	agCmd := cmdag.NewCmd()
 	agCmd.AddCommand(cmdag.NewDeployCmd(cmdag.NewDeployOptions(o.ui, o.depsFactory, o.logger, o.PreflightChecks), flagsFactory))
 	agCmd.AddCommand(cmdag.NewDeleteCmd(cmdag.NewDeleteOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
@@ -129,6 +134,7 @@ func NewKappCmd(o *KappOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 	acCmd := cmdac.NewCmd()
 	acCmd.AddCommand(cmdac.NewListCmd(cmdac.NewListOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 	acCmd.AddCommand(cmdac.NewGCCmd(cmdac.NewGCOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	acCmd.AddCommand(cmdac.NewRollbackCmd(cmdac.NewRollbackOptions(o.ui, o.depsFactory, o.logger, o.PreflightChecks), flagsFactory))
 	cmd.AddCommand(acCmd)
 
 	saCmd := cmdsa.NewCmd()
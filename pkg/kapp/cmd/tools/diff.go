@@ -11,6 +11,7 @@ import (
 
 	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
 	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
 	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 )
@@ -66,10 +67,14 @@ func (o *DiffOptions) Run() error {
 		changeViews = append(changeViews, DiffChangeView{change})
 	}
 
-	// TODO support adding custom config for mask rules?
-	ctlcap.NewChangeSetView(changeViews, nil, o.DiffFlags.ChangeSetViewOpts).Print(o.ui)
+	// Default config is only used for list merge keys
+	_, defaultConf, err := ctlconf.NewConfFromResourcesWithDefaults(nil)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	// TODO support adding custom config for mask rules?
+	return ctlcap.NewChangeSetView(changeViews, nil, defaultConf.DiffMergeKeyRules(), defaultConf.RiskRules(), o.DiffFlags.ChangeSetViewOpts).Print(o.ui)
 }
 
 func (o *DiffOptions) fileResources(files []string) ([]ctlres.Resource, error) {
@@ -128,6 +133,8 @@ func (v DiffChangeView) ApplyStrategyOp() (ctlcap.ClusterChangeApplyStrategyOp,
 // Since we are diffing changes without a cluster, there will be no wait operations
 func (v DiffChangeView) WaitOp() ctlcap.ClusterChangeWaitOp { return ctlcap.ClusterChangeWaitOpNoop }
 
+func (v DiffChangeView) DryRun() (bool, error) { return false, nil }
+
 func (v DiffChangeView) ConfigurableTextDiff() *ctldiff.ConfigurableTextDiff {
 	return v.change.ConfigurableTextDiff()
 }
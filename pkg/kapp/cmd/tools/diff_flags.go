@@ -4,7 +4,11 @@
 package tools
 
 import (
+	"fmt"
+	"strings"
+
 	ctlcap "carvel.dev/kapp/pkg/kapp/clusterapply"
+	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
 	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
 	"github.com/spf13/cobra"
 )
@@ -19,6 +23,9 @@ type DiffFlags struct {
 	UI         bool
 
 	AnchoredDiff bool
+
+	// ReportHTML is a path to write self-contained HTML report to
+	ReportHTML string
 }
 
 func (s *DiffFlags) SetWithPrefix(prefix string, cmd *cobra.Command) {
@@ -28,7 +35,7 @@ func (s *DiffFlags) SetWithPrefix(prefix string, cmd *cobra.Command) {
 
 	cmd.Flags().BoolVar(&s.Run, prefix+"run", false, "Show diff and exit successfully without any further action")
 	cmd.Flags().BoolVar(&s.ExitStatus, prefix+"exit-status", false, "Return specific exit status based on number of changes")
-	cmd.Flags().BoolVar(&s.UI, prefix+"ui-alpha", false, "Start UI server to inspect changes (alpha feature)")
+	cmd.Flags().BoolVar(&s.UI, prefix+"ui-alpha", false, "Start UI server to inspect and approve changes (alpha feature)")
 
 	cmd.Flags().BoolVar(&s.Summary, prefix+"summary", true, "Show diff summary")
 	cmd.Flags().BoolVarP(&s.Changes, prefix+"changes", "c", false, "Show changes")
@@ -36,11 +43,26 @@ func (s *DiffFlags) SetWithPrefix(prefix string, cmd *cobra.Command) {
 	cmd.Flags().IntVar(&s.Context, prefix+"context", 2, "Show number of lines around changed lines")
 	cmd.Flags().BoolVar(&s.LineNumbers, prefix+"line-numbers", true, "Show line numbers")
 	cmd.Flags().BoolVar(&s.Mask, prefix+"mask", true, "Apply masking rules")
+	cmd.Flags().BoolVar(&s.Semantic, prefix+"semantic", false, "Show field level changes for updated resources, matching list items by merge keys (e.g. containers by name)")
 
 	cmd.Flags().BoolVar(&s.AgainstLastApplied, prefix+"against-last-applied", true, "Show changes against last applied copy when possible")
 
 	cmd.Flags().StringVar(&s.Filter, prefix+"filter", "", `Set changes filter (example: {"and":[{"ops":["update"]},{"existingResource":{"kinds":["Deployment"]}]})`)
 	cmd.Flags().BoolVar(&s.ChangesYAML, prefix+"changes-yaml", false, "Print YAML to be applied")
+	cmd.Flags().Var(cmdcore.NewOneOfFlag(&s.Format, "format", ctlcap.ChangeSetFormats), prefix+"format",
+		fmt.Sprintf("Print changes in machine-readable format instead of text diff and summary (one of: %s)",
+			strings.Join(ctlcap.ChangeSetFormats, ", ")))
+	cmd.Flags().StringVar(&s.FormatOutput, prefix+"format-output", "",
+		"Write changes in machine-readable format to given path instead of printing them (json format is used unless --"+prefix+"format is specified)")
 
 	cmd.Flags().BoolVar(&s.AnchoredDiff, prefix+"anchored", false, "Allow using anchored diff for large resources")
 }
+
+// SetReportWithPrefix is only used by commands that write diff report
+func (s *DiffFlags) SetReportWithPrefix(prefix string, cmd *cobra.Command) {
+	if len(prefix) > 0 {
+		prefix += "-"
+	}
+
+	cmd.Flags().StringVar(&s.ReportHTML, prefix+"report-html", "", "Write self-contained HTML report with changes summary, diffs and ordering to given path")
+}
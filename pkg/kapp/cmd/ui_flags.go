@@ -5,7 +5,6 @@ package cmd
 
 import (
 	cmdcore "carvel.dev/kapp/pkg/kapp/cmd/core"
-	"github.com/cppforlife/go-cli-ui/ui"
 	uitable "github.com/cppforlife/go-cli-ui/ui/table"
 	"github.com/spf13/cobra"
 )
@@ -25,7 +24,7 @@ func (f *UIFlags) Set(cmd *cobra.Command, _ cmdcore.FlagsFactory) {
 	cmd.PersistentFlags().StringSliceVar(&f.Columns, "column", nil, "Filter to show only given columns")
 }
 
-func (f *UIFlags) ConfigureUI(ui *ui.ConfUI) {
+func (f *UIFlags) ConfigureUI(ui *cmdcore.ConfUI) {
 	if f.Color {
 		ui.EnableColor()
 	}
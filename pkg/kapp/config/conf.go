@@ -171,6 +171,40 @@ func (c Conf) DiffMaskRules() []DiffMaskRule {
 	return result
 }
 
+func (c Conf) DiffMergeKeyRules() []DiffMergeKeyRule {
+	var result []DiffMergeKeyRule
+	for _, config := range c.configs {
+		result = append(result, config.DiffMergeKeyRules...)
+	}
+	return result
+}
+
+func (c Conf) DeletionProtectionRules() []DeletionProtectionRule {
+	var result []DeletionProtectionRule
+	for _, config := range c.configs {
+		result = append(result, config.DeletionProtectionRules...)
+	}
+	return result
+}
+
+func (c Conf) RiskRules() []RiskRule {
+	var result []RiskRule
+	for _, config := range c.configs {
+		result = append(result, config.RiskRules...)
+	}
+	return result
+}
+
+func (c Conf) AdoptionRules() []ctlres.AdoptionRule {
+	var result []ctlres.AdoptionRule
+	for _, config := range c.configs {
+		for _, rule := range config.AdoptionRules {
+			result = append(result, rule.AsResourceAdoptionRule())
+		}
+	}
+	return result
+}
+
 func (c Conf) AdditionalLabels() map[string]string {
 	result := map[string]string{}
 	for _, config := range c.configs {
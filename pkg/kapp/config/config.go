@@ -5,6 +5,8 @@ package config
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	"carvel.dev/kapp/pkg/kapp/version"
@@ -30,8 +32,13 @@ type Config struct {
 	LabelScopingRules   []LabelScopingRule
 	TemplateRules       []TemplateRule
 	DiffMaskRules       []DiffMaskRule
+	DiffMergeKeyRules   []DiffMergeKeyRule
 	PreflightRules      []PreflightRule
 
+	DeletionProtectionRules []DeletionProtectionRule
+	AdoptionRules           []AdoptionRule
+	RiskRules               []RiskRule
+
 	AdditionalLabels                          map[string]string
 	DiffAgainstLastAppliedFieldExclusionRules []DiffAgainstLastAppliedFieldExclusionRule
 	DiffAgainstExistingFieldExclusionRules    []DiffAgainstExistingFieldExclusionRule
@@ -123,6 +130,18 @@ type TemplateRule struct {
 type DiffMaskRule struct {
 	ResourceMatchers []ResourceMatcher
 	Path             ctlres.Path
+	// Fingerprint shows salted hash of masked values and whether
+	// they were changed, added or removed instead of just hiding them
+	Fingerprint bool `json:"fingerprint"`
+}
+
+// DiffMergeKeyRule specifies field that identifies items of a list
+// so that semantic diff matches items regardless of their position
+type DiffMergeKeyRule struct {
+	ResourceMatchers []ResourceMatcher
+	Path             ctlres.Path
+	Paths            []ctlres.Path
+	MergeKey         string `json:"mergeKey"`
 }
 
 type TemplateAffectedResources struct {
@@ -147,6 +166,50 @@ type ChangeRuleBinding struct {
 	ResourceMatchers []ResourceMatcher
 }
 
+type DeletionProtectionRule struct {
+	ResourceMatchers []ResourceMatcher
+}
+
+// RiskRule classifies changes to matching resources so that
+// destructive or high-impact changes stand out in changes summary.
+// Rule applies only if all of specified conditions match.
+type RiskRule struct {
+	ResourceMatchers []ResourceMatcher
+	// Ops limits rule to given operations (create, update, delete)
+	Ops []string
+	// Strategies limits rule to given apply strategies (e.g. always-replace)
+	Strategies []string
+	// Paths limits rule to changes that set one of given fields to a different value
+	Paths []ctlres.Path
+	// OnlyAdditions limits Paths to changes that add list items or values
+	// to given fields (e.g. RBAC rules), ignoring changes that only remove them
+	OnlyAdditions bool
+	Level         string
+	Reason        string
+}
+
+const (
+	RiskLevelLow    = "low"
+	RiskLevelMedium = "medium"
+	RiskLevelHigh   = "high"
+)
+
+var (
+	// RiskLevels are ordered from the least to the most risky
+	RiskLevels  = []string{RiskLevelLow, RiskLevelMedium, RiskLevelHigh}
+	riskRuleOps = []string{"create", "update", "delete"}
+)
+
+// AdoptionRule allows existing resources that are associated
+// with other apps to be adopted without failing ownership check.
+// If both resource matchers and apps are specified, resource
+// has to match both.
+type AdoptionRule struct {
+	ResourceMatchers []ResourceMatcher
+	// Apps limits adoption to resources associated with these apps
+	Apps []string
+}
+
 type PreflightRule struct {
 	Name   string
 	Config map[string]any
@@ -224,6 +287,27 @@ func (c Config) Validate() error {
 		}
 	}
 
+	for i, rule := range c.AdoptionRules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating adoption rule %d: %w", i, err)
+		}
+	}
+
+	for i, rule := range c.DiffMergeKeyRules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating diff merge key rule %d: %w", i, err)
+		}
+	}
+
+	for i, rule := range c.RiskRules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating risk rule %d: %w", i, err)
+		}
+	}
+
 	return nil
 }
 
@@ -243,6 +327,62 @@ func (r RebaseRule) Validate() error {
 	return nil
 }
 
+func (r AdoptionRule) Validate() error {
+	if len(r.ResourceMatchers) == 0 && len(r.Apps) == 0 {
+		return fmt.Errorf("Expected either resourceMatchers or apps to be specified")
+	}
+	return nil
+}
+
+func (r RiskRule) Validate() error {
+	if !slices.Contains(RiskLevels, r.Level) {
+		return fmt.Errorf("Expected level to be one of: %s", strings.Join(RiskLevels, ", "))
+	}
+	if len(r.Reason) == 0 {
+		return fmt.Errorf("Expected reason to be specified")
+	}
+	for _, op := range r.Ops {
+		if !slices.Contains(riskRuleOps, op) {
+			return fmt.Errorf("Expected ops to be one of: %s", strings.Join(riskRuleOps, ", "))
+		}
+	}
+	if r.OnlyAdditions && len(r.Paths) == 0 {
+		return fmt.Errorf("Expected paths to be specified when onlyAdditions is enabled")
+	}
+	return nil
+}
+
+func (r DiffMergeKeyRule) Validate() error {
+	if len(r.MergeKey) == 0 {
+		return fmt.Errorf("Expected mergeKey to be specified")
+	}
+	if len(r.Path) > 0 && len(r.Paths) > 0 {
+		return fmt.Errorf("Expected only one of path or paths specified")
+	}
+	if len(r.Path) == 0 && len(r.Paths) == 0 {
+		return fmt.Errorf("Expected either path or paths to be specified")
+	}
+	return nil
+}
+
+// AllPaths returns paths to lists that rule applies to
+func (r DiffMergeKeyRule) AllPaths() []ctlres.Path {
+	if len(r.Paths) == 0 {
+		return []ctlres.Path{r.Path}
+	}
+	return r.Paths
+}
+
+func (r AdoptionRule) AsResourceAdoptionRule() ctlres.AdoptionRule {
+	rule := ctlres.AdoptionRule{Apps: r.Apps}
+	if len(r.ResourceMatchers) > 0 {
+		rule.ResourceMatcher = ctlres.AnyMatcher{
+			Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
+		}
+	}
+	return rule
+}
+
 func (r RebaseRule) AsMods() []ctlres.ResourceModWithMultiple {
 	if r.Ytt != nil {
 		switch {
@@ -333,6 +473,18 @@ func (r LabelScopingRule) AsMod(kvs map[string]string) ctlres.StringMapAppendMod
 	}
 }
 
+func (r DeletionProtectionRule) ResourceMatcher() ctlres.ResourceMatcher {
+	return ctlres.AnyMatcher{
+		Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
+	}
+}
+
+func (r RiskRule) ResourceMatcher() ctlres.ResourceMatcher {
+	return ctlres.AnyMatcher{
+		Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
+	}
+}
+
 func (r WaitRule) ResourceMatcher() ctlres.ResourceMatcher {
 	return ctlres.AnyMatcher{
 		Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
@@ -238,9 +238,11 @@ diffAgainstExistingFieldExclusionRules:
 
 diffMaskRules:
 - path: [data]
+  fingerprint: true
   resourceMatchers:
   - apiVersionKindMatcher: {apiVersion: v1, kind: Secret}
 - path: [stringData]
+  fingerprint: true
   resourceMatchers:
   - apiVersionKindMatcher: {apiVersion: v1, kind: Secret}
 
@@ -494,6 +496,148 @@ templateRules:
     - path: [spec, fetch, inline, pathsFrom, {allIndexes: true}, secretRef]
       resourceMatchers: *packageRepositoryMatchers
 
+# Match list items by their keys when showing semantic diff
+diffMergeKeyRules:
+- paths:
+  - [spec, template, spec, containers]
+  - [spec, template, spec, initContainers]
+  - [spec, template, spec, containers, {allIndexes: true}, env]
+  - [spec, template, spec, initContainers, {allIndexes: true}, env]
+  - [spec, template, spec, volumes]
+  - [spec, template, spec, imagePullSecrets]
+  mergeKey: name
+  resourceMatchers: *withPodTemplate
+- paths:
+  - [spec, jobTemplate, spec, template, spec, containers]
+  - [spec, jobTemplate, spec, template, spec, initContainers]
+  - [spec, jobTemplate, spec, template, spec, containers, {allIndexes: true}, env]
+  - [spec, jobTemplate, spec, template, spec, initContainers, {allIndexes: true}, env]
+  - [spec, jobTemplate, spec, template, spec, volumes]
+  - [spec, jobTemplate, spec, template, spec, imagePullSecrets]
+  mergeKey: name
+  resourceMatchers: *cronJob
+- paths:
+  - [spec, containers]
+  - [spec, initContainers]
+  - [spec, containers, {allIndexes: true}, env]
+  - [spec, initContainers, {allIndexes: true}, env]
+  - [spec, volumes]
+  - [spec, imagePullSecrets]
+  mergeKey: name
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Pod}
+- paths:
+  - [spec, template, spec, containers, {allIndexes: true}, ports]
+  - [spec, template, spec, initContainers, {allIndexes: true}, ports]
+  mergeKey: containerPort
+  resourceMatchers: *withPodTemplate
+- paths:
+  - [spec, jobTemplate, spec, template, spec, containers, {allIndexes: true}, ports]
+  - [spec, jobTemplate, spec, template, spec, initContainers, {allIndexes: true}, ports]
+  mergeKey: containerPort
+  resourceMatchers: *cronJob
+- paths:
+  - [spec, containers, {allIndexes: true}, ports]
+  - [spec, initContainers, {allIndexes: true}, ports]
+  mergeKey: containerPort
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Pod}
+- paths:
+  - [spec, template, spec, containers, {allIndexes: true}, volumeMounts]
+  - [spec, template, spec, initContainers, {allIndexes: true}, volumeMounts]
+  mergeKey: mountPath
+  resourceMatchers: *withPodTemplate
+- paths:
+  - [spec, jobTemplate, spec, template, spec, containers, {allIndexes: true}, volumeMounts]
+  - [spec, jobTemplate, spec, template, spec, initContainers, {allIndexes: true}, volumeMounts]
+  mergeKey: mountPath
+  resourceMatchers: *cronJob
+- paths:
+  - [spec, containers, {allIndexes: true}, volumeMounts]
+  - [spec, initContainers, {allIndexes: true}, volumeMounts]
+  mergeKey: mountPath
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Pod}
+- path: [spec, ports]
+  mergeKey: port
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Service}
+
+# Highlight destructive or high-impact changes in changes summary
+riskRules:
+- ops: [delete]
+  level: high
+  reason: deletes persistent volume (data may be lost)
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: PersistentVolumeClaim}
+  - apiVersionKindMatcher: {apiVersion: v1, kind: PersistentVolume}
+- ops: [delete]
+  level: high
+  reason: deletes custom resource definition and all of its custom resources
+  resourceMatchers:
+  - apiGroupKindMatcher: {kind: CustomResourceDefinition, apiGroup: apiextensions.k8s.io}
+- ops: [delete]
+  level: high
+  reason: deletes namespace and all resources within it
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Namespace}
+- ops: [delete]
+  level: medium
+  reason: deletes service (clients may lose connectivity)
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Service}
+- ops: [update]
+  strategies: [fallback-on-replace, always-replace]
+  level: high
+  reason: may replace stateful resource
+  resourceMatchers:
+  - apiGroupKindMatcher: {kind: StatefulSet, apiGroup: apps}
+  - apiVersionKindMatcher: {apiVersion: v1, kind: PersistentVolumeClaim}
+  - apiVersionKindMatcher: {apiVersion: v1, kind: PersistentVolume}
+- ops: [update]
+  paths:
+  - [spec, selector]
+  level: high
+  reason: changes immutable selector
+  resourceMatchers:
+  - apiGroupKindMatcher: {kind: Deployment, apiGroup: apps}
+  - apiGroupKindMatcher: {kind: StatefulSet, apiGroup: apps}
+  - apiGroupKindMatcher: {kind: DaemonSet, apiGroup: apps}
+  - apiGroupKindMatcher: {kind: ReplicaSet, apiGroup: apps}
+- ops: [update]
+  paths:
+  - [rules]
+  - [aggregationRule]
+  onlyAdditions: true
+  level: high
+  reason: grants additional cluster-wide RBAC permissions
+  resourceMatchers:
+  - apiGroupKindMatcher: {kind: ClusterRole, apiGroup: rbac.authorization.k8s.io}
+- ops: [update]
+  paths:
+  - [subjects]
+  onlyAdditions: true
+  level: high
+  reason: binds cluster role to additional subjects
+  resourceMatchers:
+  - apiGroupKindMatcher: {kind: ClusterRoleBinding, apiGroup: rbac.authorization.k8s.io}
+- ops: [update]
+  paths:
+  - [rules]
+  onlyAdditions: true
+  level: medium
+  reason: grants additional RBAC permissions
+  resourceMatchers:
+  - apiGroupKindMatcher: {kind: Role, apiGroup: rbac.authorization.k8s.io}
+- ops: [update]
+  paths:
+  - [subjects]
+  onlyAdditions: true
+  level: medium
+  reason: binds role to additional subjects
+  resourceMatchers:
+  - apiGroupKindMatcher: {kind: RoleBinding, apiGroup: rbac.authorization.k8s.io}
+
 changeGroupBindings:
 - name: change-groups.kapp.k14s.io/crds
   resourceMatchers: &crdMatchers
@@ -305,3 +305,59 @@ spec:
 		require.Equal(t, testCase.expectedDiff, diff.String())
 	}
 }
+
+func TestRiskRulesValidation(t *testing.T) {
+	_, defaultConfig, err := config.NewConfFromResourcesWithDefaults([]ctlres.Resource{})
+	require.NoError(t, err)
+	require.NotEmpty(t, defaultConfig.RiskRules())
+
+	testCases := []struct {
+		description string
+		rule        string
+		expectedErr string
+	}{
+		{
+			description: "valid rule",
+			rule:        "{level: high, reason: test, ops: [delete]}",
+		},
+		{
+			description: "unknown level",
+			rule:        "{level: severe, reason: test}",
+			expectedErr: "Validating risk rule 0: Expected level to be one of: low, medium, high",
+		},
+		{
+			description: "missing reason",
+			rule:        "{level: low}",
+			expectedErr: "Validating risk rule 0: Expected reason to be specified",
+		},
+		{
+			description: "unknown op",
+			rule:        "{level: low, reason: test, ops: [noop]}",
+			expectedErr: "Validating risk rule 0: Expected ops to be one of: create, update, delete",
+		},
+		{
+			description: "only additions without paths",
+			rule:        "{level: low, reason: test, onlyAdditions: true}",
+			expectedErr: "Validating risk rule 0: Expected paths to be specified when onlyAdditions is enabled",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			rs, err := ctlres.NewResourcesFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+riskRules:
+- ` + testCase.rule))
+			require.NoError(t, err)
+
+			_, conf, err := config.NewConfFromResources(rs)
+			if len(testCase.expectedErr) > 0 {
+				require.ErrorContains(t, err, testCase.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, conf.RiskRules(), 1)
+		})
+	}
+}
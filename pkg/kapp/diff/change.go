@@ -5,7 +5,6 @@ package diff
 
 import (
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
-	"github.com/cppforlife/go-patch/patch"
 )
 
 type ChangeOp string
@@ -112,6 +111,11 @@ func (d *ChangeImpl) op() ChangeOp {
 		return ChangeOpDelete
 	}
 
+	// Hooks are recreated every time regardless of changes
+	if _, isHook := d.newRes.Annotations()[ctlres.HookAnnKey]; isHook {
+		return ChangeOpUpdate
+	}
+
 	if d.ConfigurableTextDiff().Full().HasChanges() {
 		if d.newResHasExistsAnnotation() {
 			return ChangeOpKeep
@@ -148,7 +152,7 @@ func (d *ChangeImpl) OpsDiff() OpsDiff {
 }
 
 func (d *ChangeImpl) calculateOpsDiff() OpsDiff {
-	return OpsDiff(patch.Diff{Left: d.existingRes.UnstructuredObject(), Right: d.newRes.UnstructuredObject()}.Calculate())
+	return NewOpsDiff(d.existingRes, d.newRes)
 }
 
 func (d *ChangeImpl) newResHasExistsAnnotation() bool {
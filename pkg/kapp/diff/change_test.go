@@ -0,0 +1,29 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"testing"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeOpsDiff_AddAndDelete(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`))
+
+	addChange := ctldiff.NewChange(nil, res, res, nil, ctldiff.ChangeOpts{})
+	require.True(t, addChange.OpsDiff().HasChanges())
+
+	deleteChange := ctldiff.NewChange(res, nil, nil, res, ctldiff.ChangeOpts{})
+	require.True(t, deleteChange.OpsDiff().HasChanges())
+
+	require.NotEqual(t, addChange.OpsDiff().MinimalMD5(), deleteChange.OpsDiff().MinimalMD5())
+}
@@ -4,7 +4,6 @@
 package diff
 
 import (
-	"fmt"
 	"strings"
 
 	ctlconf "carvel.dev/kapp/pkg/kapp/config"
@@ -33,24 +32,62 @@ func (d ConfigurableTextDiff) Full() TextDiff {
 }
 
 func (d ConfigurableTextDiff) Masked(rules []ctlconf.DiffMaskRule) (TextDiff, error) {
-	var existingRes, newRes ctlres.Resource
-	var err error
+	existingRes, newRes, err := d.maskedResources(rules)
+	if err != nil {
+		return TextDiff{}, err
+	}
+	return d.calculate(existingRes, newRes), nil
+}
+
+// OpsDiff returns operations between the same resources
+// that are used for text diff (with or without masking)
+func (d ConfigurableTextDiff) OpsDiff(rules []ctlconf.DiffMaskRule, mask bool) (OpsDiff, error) {
+	existingRes, newRes := d.existingRes, d.newRes
 
-	if d.existingRes != nil {
-		existingRes, err = NewMaskedResource(d.existingRes, rules).Resource()
+	if mask {
+		var err error
+		existingRes, newRes, err = d.maskedResources(rules)
 		if err != nil {
-			return TextDiff{}, fmt.Errorf("Masking existing resource: %w", err)
+			return nil, err
 		}
 	}
 
-	if d.newRes != nil {
-		newRes, err = NewMaskedResource(d.newRes, rules).Resource()
+	if newRes == nil && d.ignored {
+		return OpsDiff{}, nil // show as no changes
+	}
+
+	return NewOpsDiff(existingRes, newRes), nil
+}
+
+// SemanticDiff returns field level diff between the same resources
+// that are used for text diff (with or without masking)
+func (d ConfigurableTextDiff) SemanticDiff(maskRules []ctlconf.DiffMaskRule, mask bool,
+	mergeKeyRules []ctlconf.DiffMergeKeyRule) (SemanticDiff, error) {
+
+	existingRes, newRes := d.existingRes, d.newRes
+
+	if mask {
+		var err error
+		existingRes, newRes, err = d.maskedResources(maskRules)
 		if err != nil {
-			return TextDiff{}, fmt.Errorf("Masking new resource: %w", err)
+			return SemanticDiff{}, err
 		}
 	}
 
-	return d.calculate(existingRes, newRes), nil
+	if newRes == nil && d.ignored {
+		newRes = existingRes // show as no changes
+	}
+
+	return NewSemanticDiff(existingRes, newRes, mergeKeyRules), nil
+}
+
+func (d ConfigurableTextDiff) maskedResources(rules []ctlconf.DiffMaskRule) (ctlres.Resource, ctlres.Resource, error) {
+	if d.newRes == nil && d.ignored {
+		// Compare against itself since it's shown as no changes
+		existingRes, _, err := NewMaskedResources(d.existingRes, d.existingRes, rules)
+		return existingRes, nil, err
+	}
+	return NewMaskedResources(d.existingRes, d.newRes, rules)
 }
 
 func (d ConfigurableTextDiff) calculate(existingRes, newRes ctlres.Resource) TextDiff {
@@ -0,0 +1,112 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"sort"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/cppforlife/go-patch/patch"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// NewDriftChange returns change from last applied copy of a resource
+// (rebased against live resource) to its live state. Only fields present
+// in last applied copy are considered, so that fields defaulted or
+// populated by the server are not reported as drift.
+// Returns false if resource does not have last applied copy.
+func (f ChangeFactory) NewDriftChange(liveRes ctlres.Resource) (Change, bool, error) {
+	lastAppliedResBytes := liveRes.Annotations()[appliedResAnnKey]
+	if len(lastAppliedResBytes) == 0 {
+		return nil, false, nil
+	}
+
+	lastAppliedRes, err := ctlres.NewResourceFromBytes([]byte(lastAppliedResBytes))
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Remove fields that are expected to be changed by the server
+	existingRes, err := NewResourceWithRemovedFields(liveRes, f.diffAgainstLastAppliedFieldExclusionMods).Resource()
+	if err != nil {
+		return nil, false, err
+	}
+
+	rebasedChange, err := f.NewExactChange(existingRes, lastAppliedRes)
+	if err != nil {
+		return nil, false, err
+	}
+
+	expectedRes := rebasedChange.NewResource()
+	actualRes := rebasedChange.ExistingResource().DeepCopy()
+
+	projectedObj := projectDriftValue(actualRes.DeepCopyRaw(), expectedRes.DeepCopyRaw())
+	projectedRes := ctlres.NewResourceUnstructured(
+		unstructured.Unstructured{Object: projectedObj.(map[string]interface{})}, ctlres.ResourceType{})
+
+	return NewChange(expectedRes, projectedRes, projectedRes, liveRes, f.opts), true, nil
+}
+
+// DriftedPaths returns sorted paths that differ in given change
+func DriftedPaths(change Change) []string {
+	opDefs, err := patch.NewOpDefinitionsFromOps(patch.Ops(change.OpsDiff()))
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	seenPaths := map[string]struct{}{}
+
+	for _, opDef := range opDefs {
+		if opDef.Path == nil {
+			continue
+		}
+		if _, found := seenPaths[*opDef.Path]; !found {
+			seenPaths[*opDef.Path] = struct{}{}
+			paths = append(paths, *opDef.Path)
+		}
+	}
+
+	sort.Strings(paths)
+
+	return paths
+}
+
+// projectDriftValue narrows actual value down to the shape of expected value:
+// map keys not present in expected value are dropped, and list items are
+// projected pairwise (extra or missing items are kept as is).
+func projectDriftValue(actual, expected interface{}) interface{} {
+	switch typedExpected := expected.(type) {
+	case map[string]interface{}:
+		typedActual, ok := actual.(map[string]interface{})
+		if !ok {
+			return actual
+		}
+		result := map[string]interface{}{}
+		for key, expectedVal := range typedExpected {
+			if actualVal, found := typedActual[key]; found {
+				result[key] = projectDriftValue(actualVal, expectedVal)
+			}
+		}
+		return result
+
+	case []interface{}:
+		typedActual, ok := actual.([]interface{})
+		if !ok {
+			return actual
+		}
+		result := []interface{}{}
+		for i, actualVal := range typedActual {
+			if i < len(typedExpected) {
+				result = append(result, projectDriftValue(actualVal, typedExpected[i]))
+			} else {
+				result = append(result, actualVal)
+			}
+		}
+		return result
+
+	default:
+		return actual
+	}
+}
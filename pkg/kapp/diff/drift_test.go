@@ -0,0 +1,77 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"testing"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDriftChange_IgnoresServerPopulatedFields(t *testing.T) {
+	liveRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-dep
+  annotations:
+    kapp.k14s.io/original: '{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"my-dep"},"spec":{"replicas":1,"template":{"spec":{"containers":[{"name":"app","image":"app:1"}]}}}}'
+spec:
+  replicas: 1
+  progressDeadlineSeconds: 600
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1
+        imagePullPolicy: IfNotPresent
+status:
+  replicas: 1
+`))
+
+	change, found, err := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{}).NewDriftChange(liveRes)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, ctldiff.ChangeOpKeep, change.Op())
+	require.Empty(t, ctldiff.DriftedPaths(change))
+}
+
+func TestNewDriftChange_ReportsChangedFields(t *testing.T) {
+	liveRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-dep
+  annotations:
+    kapp.k14s.io/original: '{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"my-dep"},"spec":{"replicas":1,"template":{"spec":{"containers":[{"name":"app","image":"app:1"}]}}}}'
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:2
+`))
+
+	change, found, err := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{}).NewDriftChange(liveRes)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, ctldiff.ChangeOpUpdate, change.Op())
+	require.Equal(t, []string{"/spec/replicas", "/spec/template/spec/containers/0/image"}, ctldiff.DriftedPaths(change))
+}
+
+func TestNewDriftChange_WithoutLastApplied(t *testing.T) {
+	liveRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-cm
+`))
+
+	_, found, err := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{}).NewDriftChange(liveRes)
+	require.NoError(t, err)
+	require.False(t, found)
+}
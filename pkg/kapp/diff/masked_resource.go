@@ -4,9 +4,14 @@
 package diff
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"sort"
+	"sync"
 
 	ctlconf "carvel.dev/kapp/pkg/kapp/config"
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
@@ -15,13 +20,44 @@ import (
 type MaskedResource struct {
 	res   ctlres.Resource
 	rules []ctlconf.DiffMaskRule
+
+	// otherRes (could be nil) is used to mark fingerprinted
+	// values that differ between existing and new resources
+	compared      bool
+	otherRes      ctlres.Resource
+	missingMarker string
 }
 
 func NewMaskedResource(res ctlres.Resource, rules []ctlconf.DiffMaskRule) MaskedResource {
 	if res == nil {
 		panic("Expected res be non-nil")
 	}
-	return MaskedResource{res, rules}
+	return MaskedResource{res: res, rules: rules}
+}
+
+// NewMaskedResources masks existing and new resources (either could be nil)
+// so that fingerprinted values indicate if they were changed, added or removed
+func NewMaskedResources(existingRes, newRes ctlres.Resource,
+	rules []ctlconf.DiffMaskRule) (ctlres.Resource, ctlres.Resource, error) {
+
+	var maskedExistingRes, maskedNewRes ctlres.Resource
+	var err error
+
+	if existingRes != nil {
+		maskedExistingRes, err = MaskedResource{existingRes, rules, true, newRes, "removed"}.Resource()
+		if err != nil {
+			return nil, nil, fmt.Errorf("Masking existing resource: %w", err)
+		}
+	}
+
+	if newRes != nil {
+		maskedNewRes, err = MaskedResource{newRes, rules, true, existingRes, "added"}.Resource()
+		if err != nil {
+			return nil, nil, fmt.Errorf("Masking new resource: %w", err)
+		}
+	}
+
+	return maskedExistingRes, maskedNewRes, nil
 }
 
 func (r MaskedResource) Resource() (ctlres.Resource, error) {
@@ -43,10 +79,15 @@ func (r MaskedResource) update(rule ctlconf.DiffMaskRule, res ctlres.Resource) e
 		Path:            rule.Path,
 		ReplacementFunc: r.maskValues,
 	}
+	if rule.Fingerprint {
+		mod.PathReplacementFunc = r.fingerprintValues
+	}
 	return mod.Apply(res)
 }
 
 var (
+	// Resources could be masked concurrently (e.g. when deploying multiple apps)
+	maskedResourceValuesLock   sync.Mutex
 	maskedResourceValues       = map[string]int{}
 	maskedResourceValueLastIdx = 1
 )
@@ -59,6 +100,9 @@ func (MaskedResource) maskValues(typedObj map[string]interface{}) error {
 	}
 	sort.Strings(sortedKeys)
 
+	maskedResourceValuesLock.Lock()
+	defer maskedResourceValuesLock.Unlock()
+
 	for _, k := range sortedKeys {
 		var maskVal string
 
@@ -83,3 +127,89 @@ func (MaskedResource) maskValues(typedObj map[string]interface{}) error {
 	}
 	return nil
 }
+
+// fingerprintValues uses location of masked values to find same values in other resource
+func (r MaskedResource) fingerprintValues(typedObj map[string]interface{}, path ctlres.Path) error {
+	var otherTypedObj map[string]interface{}
+	if r.otherRes != nil {
+		otherTypedObj = maskedResourceLookup(r.otherRes.UnstructuredObject(), path)
+	}
+
+	for k, val := range typedObj {
+		var marker string
+
+		if r.compared {
+			otherVal, found := otherTypedObj[k]
+			switch {
+			case !found:
+				marker = r.missingMarker
+			case !reflect.DeepEqual(val, otherVal):
+				marker = "changed"
+			}
+		}
+
+		typedObj[k] = maskedResourceFingerprint(val, marker)
+	}
+
+	return nil
+}
+
+func maskedResourceLookup(obj interface{}, path ctlres.Path) map[string]interface{} {
+	for _, part := range path {
+		switch {
+		case part.MapKey != nil:
+			typedObj, ok := obj.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			obj = typedObj[*part.MapKey]
+
+		case part.ArrayIndex != nil && part.ArrayIndex.Index != nil:
+			typedObj, ok := obj.([]interface{})
+			if !ok || *part.ArrayIndex.Index >= len(typedObj) {
+				return nil
+			}
+			obj = typedObj[*part.ArrayIndex.Index]
+		}
+	}
+
+	typedObj, _ := obj.(map[string]interface{})
+	return typedObj
+}
+
+var (
+	maskedResourceFingerprintSalt     []byte
+	maskedResourceFingerprintSaltOnce sync.Once
+)
+
+// maskedResourceFingerprint uses salt that is randomly generated per process
+// so that fingerprints cannot be compared against hashes of known values;
+// hence they are only comparable within the same diff
+func maskedResourceFingerprint(val interface{}, marker string) string {
+	maskedResourceFingerprintSaltOnce.Do(func() {
+		maskedResourceFingerprintSalt = make([]byte, 32)
+		_, err := rand.Read(maskedResourceFingerprintSalt)
+		if err != nil {
+			panic(fmt.Sprintf("Generating fingerprint salt: %s", err))
+		}
+	})
+
+	valBs, err := json.Marshal(val)
+	if err != nil {
+		// Same as with indexed values, prefer to show a change
+		maskedResourceValuesLock.Lock()
+		defer maskedResourceValuesLock.Unlock()
+
+		maskVal := fmt.Sprintf("<-- unknown value not shown (#%d)", maskedResourceValueLastIdx)
+		maskedResourceValueLastIdx++
+		return maskVal
+	}
+
+	hash := sha256.Sum256(append(append([]byte{}, maskedResourceFingerprintSalt...), valBs...))
+	fingerprint := hex.EncodeToString(hash[:])[:8]
+
+	if len(marker) > 0 {
+		return fmt.Sprintf("<-- value not shown (fingerprint: %s, %s)", fingerprint, marker)
+	}
+	return fmt.Sprintf("<-- value not shown (fingerprint: %s)", fingerprint)
+}
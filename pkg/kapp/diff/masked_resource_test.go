@@ -0,0 +1,165 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"regexp"
+	"sync"
+	"testing"
+
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMaskedResources_Fingerprint(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+data:
+  same: c2FtZQ==
+  rotated: b2xk
+  removed: cmVtb3ZlZA==
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+data:
+  same: c2FtZQ==
+  rotated: bmV3
+  added: YWRkZWQ=
+`))
+
+	rules := []ctlconf.DiffMaskRule{{
+		Path:             ctlres.NewPathFromStrings([]string{"data"}),
+		Fingerprint:      true,
+		ResourceMatchers: []ctlconf.ResourceMatcher{{AllMatcher: &ctlconf.AllMatcher{}}},
+	}}
+
+	maskedExistingRes, maskedNewRes, err := ctldiff.NewMaskedResources(existingRes, newRes, rules)
+	require.NoError(t, err)
+
+	existingData := maskedExistingRes.UnstructuredObject()["data"].(map[string]interface{})
+	newData := maskedNewRes.UnstructuredObject()["data"].(map[string]interface{})
+
+	fingerprint := func(val interface{}, expectedMarker string) string {
+		matches := regexp.MustCompile(`^<-- value not shown \(fingerprint: ([0-9a-f]{8})(, (\w+))?\)$`).FindStringSubmatch(val.(string))
+		require.Len(t, matches, 4, "Unexpected masked value: %s", val)
+		require.Equal(t, expectedMarker, matches[3])
+		return matches[1]
+	}
+
+	require.Equal(t, fingerprint(existingData["same"], ""), fingerprint(newData["same"], ""))
+	require.NotEqual(t, fingerprint(existingData["rotated"], "changed"), fingerprint(newData["rotated"], "changed"))
+	fingerprint(existingData["removed"], "removed")
+	fingerprint(newData["added"], "added")
+
+	require.Equal(t, "b2xk", existingRes.UnstructuredObject()["data"].(map[string]interface{})["rotated"],
+		"Expected original resource to not be modified")
+}
+
+func TestNewMaskedResources_FingerprintWithoutOtherResource(t *testing.T) {
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+data:
+  key: dmFs
+`))
+
+	rules := []ctlconf.DiffMaskRule{{
+		Path:             ctlres.NewPathFromStrings([]string{"data"}),
+		Fingerprint:      true,
+		ResourceMatchers: []ctlconf.ResourceMatcher{{AllMatcher: &ctlconf.AllMatcher{}}},
+	}}
+
+	maskedExistingRes, maskedNewRes, err := ctldiff.NewMaskedResources(nil, newRes, rules)
+	require.NoError(t, err)
+	require.Nil(t, maskedExistingRes)
+	require.Regexp(t, `^<-- value not shown \(fingerprint: [0-9a-f]{8}, added\)$`,
+		maskedNewRes.UnstructuredObject()["data"].(map[string]interface{})["key"])
+
+	maskedRes, err := ctldiff.NewMaskedResource(newRes, rules).Resource()
+	require.NoError(t, err)
+	require.Regexp(t, `^<-- value not shown \(fingerprint: [0-9a-f]{8}\)$`,
+		maskedRes.UnstructuredObject()["data"].(map[string]interface{})["key"])
+}
+
+func TestNewMaskedResources_FingerprintInLists(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: List
+metadata:
+  name: list
+items:
+- data: {key: a}
+- data: {key: b}
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: List
+metadata:
+  name: list
+items:
+- data: {key: a}
+- data: {key: c}
+- data: {key: d}
+`))
+
+	rules := []ctlconf.DiffMaskRule{{
+		Path:             ctlres.Path{ctlres.NewPathPartFromString("items"), ctlres.NewPathPartFromIndexAll(), ctlres.NewPathPartFromString("data")},
+		Fingerprint:      true,
+		ResourceMatchers: []ctlconf.ResourceMatcher{{AllMatcher: &ctlconf.AllMatcher{}}},
+	}}
+
+	_, maskedNewRes, err := ctldiff.NewMaskedResources(existingRes, newRes, rules)
+	require.NoError(t, err)
+
+	items := maskedNewRes.UnstructuredObject()["items"].([]interface{})
+	markers := []string{"", ", changed", ", added"}
+
+	for i, item := range items {
+		require.Regexp(t, `^<-- value not shown \(fingerprint: [0-9a-f]{8}`+markers[i]+`\)$`,
+			item.(map[string]interface{})["data"].(map[string]interface{})["key"])
+	}
+}
+
+func TestNewMaskedResources_Concurrent(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  key1: val1
+  key2: val2
+`))
+
+	rules := []ctlconf.DiffMaskRule{{
+		Path:             ctlres.NewPathFromStrings([]string{"data"}),
+		ResourceMatchers: []ctlconf.ResourceMatcher{{AllMatcher: &ctlconf.AllMatcher{}}},
+	}}
+
+	var wg sync.WaitGroup
+
+	// Resources are masked concurrently when multiple apps are deployed at once
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := ctldiff.NewMaskedResources(res, res.DeepCopy(), rules)
+			require.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+}
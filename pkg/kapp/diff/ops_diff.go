@@ -6,13 +6,28 @@ package diff
 import (
 	"crypto/md5"
 	"fmt"
+	"strings"
 
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
 	"github.com/cppforlife/go-patch/patch"
 	"gopkg.in/yaml.v2"
 )
 
 type OpsDiff patch.Ops
 
+// NewOpsDiff calculates operations to turn existing resource into new one.
+// Missing resource (when adding or deleting) is represented as nil.
+func NewOpsDiff(existingRes, newRes ctlres.Resource) OpsDiff {
+	var left, right interface{}
+	if existingRes != nil {
+		left = existingRes.UnstructuredObject()
+	}
+	if newRes != nil {
+		right = newRes.UnstructuredObject()
+	}
+	return OpsDiff(patch.Diff{Left: left, Right: right}.Calculate())
+}
+
 func (l OpsDiff) HasChanges() bool { return len(l) > 0 }
 
 func (l OpsDiff) MinimalMD5() string {
@@ -34,3 +49,114 @@ func (l OpsDiff) MinimalString() string {
 
 	return string(bs)
 }
+
+// OpsDiffOperation is a serializable form of a single go-patch operation
+type OpsDiffOperation struct {
+	Type   string       `json:"type"`
+	Path   string       `json:"path"`
+	Value  *interface{} `json:"value,omitempty"`
+	Absent bool         `json:"absent,omitempty"`
+}
+
+func (l OpsDiff) Operations() []OpsDiffOperation {
+	result := []OpsDiffOperation{}
+
+	for _, op := range l {
+		switch typedOp := op.(type) {
+		case patch.TestOp:
+			resultOp := OpsDiffOperation{Type: "test", Path: typedOp.Path.String(), Absent: typedOp.Absent}
+			if !typedOp.Absent {
+				resultOp.Value = &typedOp.Value
+			}
+			result = append(result, resultOp)
+
+		case patch.ReplaceOp:
+			result = append(result, OpsDiffOperation{Type: "replace", Path: typedOp.Path.String(), Value: &typedOp.Value})
+
+		case patch.RemoveOp:
+			result = append(result, OpsDiffOperation{Type: "remove", Path: typedOp.Path.String()})
+
+		default:
+			panic(fmt.Sprintf("Unknown ops diff operation: %T", op))
+		}
+	}
+
+	return result
+}
+
+// JSONPatchOperation is a single RFC 6902 JSON patch operation
+type JSONPatchOperation struct {
+	Op    string       `json:"op"`
+	Path  string       `json:"path"`
+	Value *interface{} `json:"value,omitempty"`
+}
+
+// JSONPatch converts operations to JSON patch. Checks that fields are absent
+// cannot be expressed in JSON patch, hence they are dropped; other checks
+// are kept as test operations (they capture previous values).
+func (l OpsDiff) JSONPatch() []JSONPatchOperation {
+	result := []JSONPatchOperation{}
+
+	for _, op := range l {
+		switch typedOp := op.(type) {
+		case patch.TestOp:
+			if !typedOp.Absent {
+				result = append(result, JSONPatchOperation{Op: "test", Path: jsonPointer(typedOp.Path), Value: &typedOp.Value})
+			}
+
+		case patch.ReplaceOp:
+			opName := "replace"
+			if isAddingPointer(typedOp.Path) {
+				opName = "add"
+			}
+			result = append(result, JSONPatchOperation{Op: opName, Path: jsonPointer(typedOp.Path), Value: &typedOp.Value})
+
+		case patch.RemoveOp:
+			result = append(result, JSONPatchOperation{Op: "remove", Path: jsonPointer(typedOp.Path)})
+
+		default:
+			panic(fmt.Sprintf("Unknown ops diff operation: %T", op))
+		}
+	}
+
+	return result
+}
+
+// isAddingPointer returns true for pointers that refer to
+// map keys or list items that do not exist yet
+func isAddingPointer(pointer patch.Pointer) bool {
+	tokens := pointer.Tokens()
+	if len(tokens) == 0 {
+		return false
+	}
+	switch typedToken := tokens[len(tokens)-1].(type) {
+	case patch.KeyToken:
+		return typedToken.Optional
+	case patch.AfterLastIndexToken:
+		return true
+	default:
+		return false
+	}
+}
+
+func jsonPointer(pointer patch.Pointer) string {
+	var sb strings.Builder
+
+	for _, token := range pointer.Tokens() {
+		switch typedToken := token.(type) {
+		case patch.RootToken:
+			// nothing to add
+		case patch.KeyToken:
+			key := strings.ReplaceAll(typedToken.Key, "~", "~0")
+			sb.WriteString("/" + strings.ReplaceAll(key, "/", "~1"))
+		case patch.IndexToken:
+			sb.WriteString(fmt.Sprintf("/%d", typedToken.Index))
+		case patch.AfterLastIndexToken:
+			sb.WriteString("/-")
+		default:
+			panic(fmt.Sprintf("Unknown ops diff pointer token: %T", token))
+		}
+	}
+
+	return sb.String()
+}
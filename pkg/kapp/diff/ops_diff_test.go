@@ -0,0 +1,91 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpsDiffJSONPatch(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  labels:
+    a/b: val
+data:
+  removed: val
+  updated: old
+list:
+- 1
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  labels:
+    a/b: val
+    x/y: val
+data:
+  updated: new
+list:
+- 1
+- 2
+`))
+
+	bs, err := json.Marshal(ctldiff.NewOpsDiff(existingRes, newRes).JSONPatch())
+	require.NoError(t, err)
+
+	expected := `[` +
+		`{"op":"test","path":"/data/removed","value":"val"},` +
+		`{"op":"remove","path":"/data/removed"},` +
+		`{"op":"test","path":"/data/updated","value":"old"},` +
+		`{"op":"replace","path":"/data/updated","value":"new"},` +
+		`{"op":"add","path":"/list/-","value":2},` +
+		`{"op":"add","path":"/metadata/labels/x~1y","value":"val"}` +
+		`]`
+
+	require.Equal(t, expected, string(bs))
+}
+
+func TestOpsDiffOperations(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  key: old
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  key: new
+  added: val
+`))
+
+	bs, err := json.Marshal(ctldiff.NewOpsDiff(existingRes, newRes).Operations())
+	require.NoError(t, err)
+
+	expected := `[` +
+		`{"type":"test","path":"/data/added","absent":true},` +
+		`{"type":"replace","path":"/data/added?","value":"val"},` +
+		`{"type":"test","path":"/data/key","value":"old"},` +
+		`{"type":"replace","path":"/data/key","value":"new"}` +
+		`]`
+
+	require.Equal(t, expected, string(bs))
+}
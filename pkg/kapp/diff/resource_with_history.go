@@ -63,6 +63,22 @@ func (r ResourceWithHistory) LastAppliedResource() ctlres.Resource {
 	return nil
 }
 
+// RecordedLastAppliedResource returns last applied resource as recorded,
+// without checking whether resource was changed since (unlike LastAppliedResource)
+func (r ResourceWithHistory) RecordedLastAppliedResource() ctlres.Resource {
+	lastAppliedResBytes := r.resource.Annotations()[appliedResAnnKey]
+	if len(lastAppliedResBytes) == 0 {
+		return nil
+	}
+
+	lastAppliedRes, err := ctlres.NewResourceFromBytes([]byte(lastAppliedResBytes))
+	if err != nil {
+		return nil
+	}
+
+	return lastAppliedRes
+}
+
 func (r ResourceWithHistory) AllowsRecordingLastApplied() bool {
 	_, found := r.resource.Annotations()[disableOriginalAnnKey]
 	return !found
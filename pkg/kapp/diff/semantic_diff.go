@@ -0,0 +1,314 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+)
+
+type SemanticDiffChangeType string
+
+const (
+	SemanticDiffChangeAdd    SemanticDiffChangeType = "add"
+	SemanticDiffChangeRemove SemanticDiffChangeType = "remove"
+	SemanticDiffChangeModify SemanticDiffChangeType = "modify"
+)
+
+type SemanticDiffChange struct {
+	Type SemanticDiffChangeType
+	// Path is human readable location of a change
+	// (eg spec.template.spec.containers[name=app].image)
+	Path string
+
+	ExistingValue interface{}
+	NewValue      interface{}
+}
+
+// SemanticDiff compares resources field by field; items of lists
+// that have merge keys configured are matched by their keys,
+// so that reordering or inserting items is shown as item level changes.
+type SemanticDiff struct {
+	existingRes, newRes ctlres.Resource
+	mergeKeyPaths       []semanticDiffMergeKeyPath
+}
+
+type semanticDiffMergeKeyPath struct {
+	Path     ctlres.Path
+	MergeKey string
+}
+
+func NewSemanticDiff(existingRes, newRes ctlres.Resource, rules []ctlconf.DiffMergeKeyRule) SemanticDiff {
+	res := newRes
+	if res == nil {
+		res = existingRes
+	}
+
+	var mergeKeyPaths []semanticDiffMergeKeyPath
+
+	if res != nil {
+		for _, rule := range rules {
+			matcher := ctlres.AnyMatcher{Matchers: ctlconf.ResourceMatchers(rule.ResourceMatchers).AsResourceMatchers()}
+			if !matcher.Matches(res) {
+				continue
+			}
+			for _, path := range rule.AllPaths() {
+				mergeKeyPaths = append(mergeKeyPaths, semanticDiffMergeKeyPath{path, rule.MergeKey})
+			}
+		}
+	}
+
+	return SemanticDiff{existingRes, newRes, mergeKeyPaths}
+}
+
+func (d SemanticDiff) Changes() []SemanticDiffChange {
+	var existingObj, newObj interface{}
+	if d.existingRes != nil {
+		existingObj = d.existingRes.UnstructuredObject()
+	}
+	if d.newRes != nil {
+		newObj = d.newRes.UnstructuredObject()
+	}
+	return d.calculate(existingObj, newObj, nil, "")
+}
+
+func (d SemanticDiff) HasChanges() bool { return len(d.Changes()) > 0 }
+
+// calculate compares values at given location; location is tracked both
+// as a list of keys and indexes (to match merge key paths) and as a
+// human readable string
+func (d SemanticDiff) calculate(existing, new interface{}, path []interface{}, pathStr string) []SemanticDiffChange {
+	switch typedExisting := existing.(type) {
+	case map[string]interface{}:
+		typedNew, ok := new.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		var keys []string
+		for key := range typedExisting {
+			keys = append(keys, key)
+		}
+		for key := range typedNew {
+			if _, found := typedExisting[key]; !found {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		var changes []SemanticDiffChange
+
+		for _, key := range keys {
+			existingVal, existingFound := typedExisting[key]
+			newVal, newFound := typedNew[key]
+			keyPathStr := semanticDiffKeyPath(pathStr, key)
+
+			switch {
+			case !existingFound:
+				changes = append(changes, SemanticDiffChange{Type: SemanticDiffChangeAdd, Path: keyPathStr, NewValue: newVal})
+			case !newFound:
+				changes = append(changes, SemanticDiffChange{Type: SemanticDiffChangeRemove, Path: keyPathStr, ExistingValue: existingVal})
+			default:
+				changes = append(changes, d.calculate(existingVal, newVal, semanticDiffAppendPath(path, key), keyPathStr)...)
+			}
+		}
+
+		return changes
+
+	case []interface{}:
+		typedNew, ok := new.([]interface{})
+		if !ok {
+			break
+		}
+
+		if mergeKey, found := d.mergeKey(path); found {
+			changes, ok := d.calculateKeyedList(typedExisting, typedNew, mergeKey, path, pathStr)
+			if ok {
+				return changes
+			}
+		}
+
+		var changes []SemanticDiffChange
+
+		for i := 0; i < max(len(typedExisting), len(typedNew)); i++ {
+			itemPathStr := fmt.Sprintf("%s[%d]", pathStr, i)
+
+			switch {
+			case i >= len(typedExisting):
+				changes = append(changes, SemanticDiffChange{Type: SemanticDiffChangeAdd, Path: itemPathStr, NewValue: typedNew[i]})
+			case i >= len(typedNew):
+				changes = append(changes, SemanticDiffChange{Type: SemanticDiffChangeRemove, Path: itemPathStr, ExistingValue: typedExisting[i]})
+			default:
+				changes = append(changes, d.calculate(typedExisting[i], typedNew[i], semanticDiffAppendPath(path, i), itemPathStr)...)
+			}
+		}
+
+		return changes
+	}
+
+	if reflect.DeepEqual(semanticDiffValue(existing), semanticDiffValue(new)) {
+		return nil
+	}
+
+	return []SemanticDiffChange{{Type: SemanticDiffChangeModify, Path: pathStr, ExistingValue: existing, NewValue: new}}
+}
+
+// calculateKeyedList matches items by merge key; returns false if items
+// cannot be matched (eg key is missing or not unique)
+func (d SemanticDiff) calculateKeyedList(existing, new []interface{}, mergeKey string,
+	path []interface{}, pathStr string) ([]SemanticDiffChange, bool) {
+
+	existingKeys, ok := semanticDiffItemKeys(existing, mergeKey)
+	if !ok {
+		return nil, false
+	}
+
+	newKeys, ok := semanticDiffItemKeys(new, mergeKey)
+	if !ok {
+		return nil, false
+	}
+
+	existingIdxs := map[string]int{}
+	for i, key := range existingKeys {
+		existingIdxs[key] = i
+	}
+
+	newIdxs := map[string]int{}
+	for i, key := range newKeys {
+		newIdxs[key] = i
+	}
+
+	var changes []SemanticDiffChange
+
+	for i, key := range newKeys {
+		itemPathStr := fmt.Sprintf("%s[%s=%s]", pathStr, mergeKey, key)
+
+		existingIdx, found := existingIdxs[key]
+		if !found {
+			changes = append(changes, SemanticDiffChange{Type: SemanticDiffChangeAdd, Path: itemPathStr, NewValue: new[i]})
+			continue
+		}
+
+		changes = append(changes, d.calculate(existing[existingIdx], new[i], semanticDiffAppendPath(path, i), itemPathStr)...)
+	}
+
+	for i, key := range existingKeys {
+		if _, found := newIdxs[key]; !found {
+			itemPathStr := fmt.Sprintf("%s[%s=%s]", pathStr, mergeKey, key)
+			changes = append(changes, SemanticDiffChange{Type: SemanticDiffChangeRemove, Path: itemPathStr, ExistingValue: existing[i]})
+		}
+	}
+
+	return changes, true
+}
+
+func (d SemanticDiff) mergeKey(path []interface{}) (string, bool) {
+	for _, mergeKeyPath := range d.mergeKeyPaths {
+		if semanticDiffPathMatches(mergeKeyPath.Path, path) {
+			return mergeKeyPath.MergeKey, true
+		}
+	}
+	return "", false
+}
+
+func semanticDiffPathMatches(expected ctlres.Path, actual []interface{}) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+
+	for i, part := range expected {
+		switch typedActual := actual[i].(type) {
+		case string:
+			switch {
+			case part.MapKey != nil:
+				if *part.MapKey != typedActual {
+					return false
+				}
+			case part.Regex != nil && part.Regex.Regex != nil:
+				matched, err := regexp.MatchString(*part.Regex.Regex, typedActual)
+				if err != nil || !matched {
+					return false
+				}
+			default:
+				return false
+			}
+
+		case int:
+			switch {
+			case part.ArrayIndex == nil:
+				return false
+			case part.ArrayIndex.All != nil && *part.ArrayIndex.All:
+				// matches any index
+			case part.ArrayIndex.Index != nil:
+				if *part.ArrayIndex.Index != typedActual {
+					return false
+				}
+			default:
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func semanticDiffItemKeys(items []interface{}, mergeKey string) ([]string, bool) {
+	var keys []string
+	seenKeys := map[string]struct{}{}
+
+	for _, item := range items {
+		typedItem, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		val, found := typedItem[mergeKey]
+		if !found {
+			return nil, false
+		}
+		key := fmt.Sprintf("%v", val)
+		if _, found := seenKeys[key]; found {
+			return nil, false
+		}
+		seenKeys[key] = struct{}{}
+		keys = append(keys, key)
+	}
+
+	return keys, true
+}
+
+func semanticDiffAppendPath(path []interface{}, part interface{}) []interface{} {
+	return append(append([]interface{}{}, path...), part)
+}
+
+var semanticDiffSimpleKey = regexp.MustCompile(`^[A-Za-z0-9_\-]+$`)
+
+func semanticDiffKeyPath(pathStr, key string) string {
+	if !semanticDiffSimpleKey.MatchString(key) {
+		return fmt.Sprintf("%s[%q]", pathStr, key)
+	}
+	if len(pathStr) == 0 {
+		return key
+	}
+	return pathStr + "." + key
+}
+
+// semanticDiffValue normalizes numbers since resources
+// may come from either JSON or YAML
+func semanticDiffValue(val interface{}) interface{} {
+	switch typedVal := val.(type) {
+	case int:
+		return float64(typedVal)
+	case int64:
+		return float64(typedVal)
+	case float32:
+		return float64(typedVal)
+	default:
+		return val
+	}
+}
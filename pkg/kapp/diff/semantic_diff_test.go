@@ -0,0 +1,195 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	ctlconf "carvel.dev/kapp/pkg/kapp/config"
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSemanticDiff_MatchesListItemsByMergeKey(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1
+        env:
+        - name: A
+          value: a
+        - name: B
+          value: b
+        ports:
+        - containerPort: 80
+      - name: sidecar
+        image: sidecar:1
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: init
+        image: init:1
+      - name: app
+        image: app:2
+        env:
+        - name: B
+          value: b
+        - name: NEW
+          value: new
+        - name: A
+          value: a
+        ports:
+        - containerPort: 8080
+`))
+
+	changes := ctldiff.NewSemanticDiff(existingRes, newRes, defaultMergeKeyRules(t)).Changes()
+
+	expected := `
+add spec.template.spec.containers[name=init]
+add spec.template.spec.containers[name=app].env[name=NEW]
+modify spec.template.spec.containers[name=app].image
+add spec.template.spec.containers[name=app].ports[containerPort=8080]
+remove spec.template.spec.containers[name=app].ports[containerPort=80]
+remove spec.template.spec.containers[name=sidecar]
+`
+	require.Equal(t, strings.TrimSpace(expected), semanticDiffChangesDesc(changes))
+
+	require.Equal(t, "app:1", changes[2].ExistingValue)
+	require.Equal(t, "app:2", changes[2].NewValue)
+}
+
+func TestSemanticDiff_CustomMergeKeyRule(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  steps:
+  - id: build
+    run: make
+  - id: test
+    run: make test
+  labels:
+    a.b/c: val
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  steps:
+  - id: lint
+    run: make lint
+  - id: build
+    run: make
+  - id: test
+    run: make test
+  labels:
+    a.b/c: other
+`))
+
+	withoutRules := ctldiff.NewSemanticDiff(existingRes, newRes, nil).Changes()
+
+	expected := `
+modify spec.labels["a.b/c"]
+modify spec.steps[0].id
+modify spec.steps[0].run
+modify spec.steps[1].id
+modify spec.steps[1].run
+add spec.steps[2]
+`
+	require.Equal(t, strings.TrimSpace(expected), semanticDiffChangesDesc(withoutRules))
+
+	config := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+diffMergeKeyRules:
+- path: [spec, steps]
+  mergeKey: id
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: example.com/v1, kind: Pipeline}
+`))
+
+	_, conf, err := ctlconf.NewConfFromResources([]ctlres.Resource{config})
+	require.NoError(t, err)
+
+	withRules := ctldiff.NewSemanticDiff(existingRes, newRes, conf.DiffMergeKeyRules()).Changes()
+
+	expected = `
+modify spec.labels["a.b/c"]
+add spec.steps[id=lint]
+`
+	require.Equal(t, strings.TrimSpace(expected), semanticDiffChangesDesc(withRules))
+}
+
+func TestSemanticDiff_FallsBackToIndexesForDuplicateKeys(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  ports:
+  - port: 53
+    protocol: TCP
+  - port: 53
+    protocol: UDP
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  ports:
+  - port: 53
+    protocol: UDP
+  - port: 53
+    protocol: TCP
+`))
+
+	changes := ctldiff.NewSemanticDiff(existingRes, newRes, defaultMergeKeyRules(t)).Changes()
+
+	expected := `
+modify spec.ports[0].protocol
+modify spec.ports[1].protocol
+`
+	require.Equal(t, strings.TrimSpace(expected), semanticDiffChangesDesc(changes))
+}
+
+func defaultMergeKeyRules(t *testing.T) []ctlconf.DiffMergeKeyRule {
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(nil)
+	require.NoError(t, err)
+	return conf.DiffMergeKeyRules()
+}
+
+func semanticDiffChangesDesc(changes []ctldiff.SemanticDiffChange) string {
+	var lines []string
+	for _, change := range changes {
+		lines = append(lines, fmt.Sprintf("%s %s", change.Type, change.Path))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cppforlife/color"
+	"gopkg.in/yaml.v2"
+)
+
+type SemanticDiffView struct {
+	diff SemanticDiff
+}
+
+func NewSemanticDiffView(diff SemanticDiff) SemanticDiffView {
+	return SemanticDiffView{diff}
+}
+
+func (v SemanticDiffView) String() string {
+	changes := v.diff.Changes()
+	if len(changes) == 0 {
+		return "  (no changes besides ordering of list items)\n"
+	}
+
+	var lines []string
+
+	for _, change := range changes {
+		switch change.Type {
+		case SemanticDiffChangeAdd:
+			lines = append(lines, v.valueLines(color.FgGreen, "+", change.Path, change.NewValue)...)
+
+		case SemanticDiffChangeRemove:
+			lines = append(lines, v.valueLines(color.FgRed, "-", change.Path, change.ExistingValue)...)
+
+		case SemanticDiffChangeModify:
+			existingVal, existingScalar := v.scalar(change.ExistingValue)
+			newVal, newScalar := v.scalar(change.NewValue)
+
+			if existingScalar && newScalar {
+				lines = append(lines, color.New(color.FgYellow).Sprintf("  ~ %s: %s -> %s", change.Path, existingVal, newVal))
+			} else {
+				lines = append(lines, color.New(color.FgYellow).Sprintf("  ~ %s:", change.Path))
+				lines = append(lines, v.valueLines(color.FgRed, "  -", "", change.ExistingValue)...)
+				lines = append(lines, v.valueLines(color.FgGreen, "  +", "", change.NewValue)...)
+			}
+
+		default:
+			panic(fmt.Sprintf("Unknown semantic diff change type: %s", change.Type))
+		}
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// valueLines shows scalar values inline and other values
+// as indented YAML under change path
+func (v SemanticDiffView) valueLines(fg color.Attribute, mark, path string, val interface{}) []string {
+	c := color.New(fg)
+
+	prefix := mark
+	if len(path) > 0 {
+		prefix = mark + " " + path + ":"
+	}
+
+	if scalarVal, ok := v.scalar(val); ok {
+		return []string{c.Sprintf("  %s %s", prefix, scalarVal)}
+	}
+
+	lines := []string{c.Sprintf("  %s", prefix)}
+
+	for _, line := range v.yamlLines(val) {
+		lines = append(lines, c.Sprintf("  %s   %s", strings.Repeat(" ", len(mark)), line))
+	}
+
+	return lines
+}
+
+func (v SemanticDiffView) scalar(val interface{}) (string, bool) {
+	switch val.(type) {
+	case map[string]interface{}, []interface{}:
+		return "", false
+	}
+	lines := v.yamlLines(val)
+	if len(lines) != 1 {
+		return "", false
+	}
+	return lines[0], true
+}
+
+func (SemanticDiffView) yamlLines(val interface{}) []string {
+	bs, err := yaml.Marshal(val)
+	if err != nil {
+		return []string{fmt.Sprintf("<-- error serializing value: %s", err)}
+	}
+	return strings.Split(strings.TrimSuffix(string(bs), "\n"), "\n")
+}
@@ -65,3 +65,86 @@ func (l TextDiff) String(full bool) string {
 
 	return sb.String()
 }
+
+// UnifiedString formats diff in unified diff format
+// with given number of lines around changed lines (<0 for all)
+func (l TextDiff) UnifiedString(fromName, toName string, context int) string {
+	if context < 0 {
+		context = len(l.recs)
+	}
+
+	var hunks [][2]int // start and end (exclusive) record indexes
+
+	for i, diff := range l.recs {
+		if diff.Delta == difflib.Common {
+			continue
+		}
+		start := max(i-context, 0)
+		end := min(i+context+1, len(l.recs))
+		if len(hunks) > 0 && start <= hunks[len(hunks)-1][1] {
+			hunks[len(hunks)-1][1] = end
+		} else {
+			hunks = append(hunks, [2]int{start, end})
+		}
+	}
+
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	// Line numbers before each record (1-based)
+	leftLines := make([]int, len(l.recs))
+	rightLines := make([]int, len(l.recs))
+	leftLine, rightLine := 1, 1
+
+	for i, diff := range l.recs {
+		leftLines[i], rightLines[i] = leftLine, rightLine
+		if diff.Delta != difflib.RightOnly {
+			leftLine++
+		}
+		if diff.Delta != difflib.LeftOnly {
+			rightLine++
+		}
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", fromName, toName))
+
+	for _, hunk := range hunks {
+		var leftCount, rightCount int
+		var lines strings.Builder
+
+		for _, diff := range l.recs[hunk[0]:hunk[1]] {
+			switch diff.Delta {
+			case difflib.RightOnly:
+				rightCount++
+				lines.WriteString("+" + diff.Payload + "\n")
+			case difflib.LeftOnly:
+				leftCount++
+				lines.WriteString("-" + diff.Payload + "\n")
+			case difflib.Common:
+				leftCount++
+				rightCount++
+				lines.WriteString(" " + diff.Payload + "\n")
+			}
+		}
+
+		sb.WriteString(fmt.Sprintf("@@ -%s +%s @@\n",
+			unifiedRange(leftLines[hunk[0]], leftCount), unifiedRange(rightLines[hunk[0]], rightCount)))
+		sb.WriteString(lines.String())
+	}
+
+	return sb.String()
+}
+
+func unifiedRange(start, count int) string {
+	if count == 0 {
+		// Empty range refers to the line before it
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"strings"
+	"testing"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextDiffUnifiedString(t *testing.T) {
+	existingLines := strings.Split("a\nb\nc\nd\ne\nf\ng\nh", "\n")
+	newLines := strings.Split("a\nB\nc\nd\ne\nf\ng\nh\ni", "\n")
+
+	textDiff := ctldiff.NewTextDiff(existingLines, newLines, false)
+
+	expected := `--- a/res
++++ b/res
+@@ -1,3 +1,3 @@
+ a
+-b
++B
+ c
+@@ -8 +8,2 @@
+ h
++i
+`
+	require.Equal(t, expected, textDiff.UnifiedString("a/res", "b/res", 1))
+
+	expected = `--- a/res
++++ b/res
+@@ -1,8 +1,9 @@
+ a
+-b
++B
+ c
+ d
+ e
+ f
+ g
+ h
++i
+`
+	require.Equal(t, expected, textDiff.UnifiedString("a/res", "b/res", -1))
+}
+
+func TestTextDiffUnifiedStringForNewResource(t *testing.T) {
+	textDiff := ctldiff.NewTextDiff(nil, []string{"a", "b"}, false)
+
+	expected := `--- a/res
++++ b/res
+@@ -0,0 +1,2 @@
++a
++b
+`
+	require.Equal(t, expected, textDiff.UnifiedString("a/res", "b/res", 2))
+	require.Equal(t, "", ctldiff.NewTextDiff([]string{"a"}, []string{"a"}, false).UnifiedString("a/res", "b/res", 2))
+}
@@ -30,20 +30,58 @@ func NewTextDiffView(diff *ConfigurableTextDiff,
 	return TextDiffView{diff, maskRules, opts}
 }
 
+type TextDiffViewLineType string
+
+const (
+	TextDiffViewLineAdded   TextDiffViewLineType = "added"
+	TextDiffViewLineRemoved TextDiffViewLineType = "removed"
+	TextDiffViewLineCommon  TextDiffViewLineType = "common"
+	// TextDiffViewLineSkipped indicates that common lines were omitted
+	TextDiffViewLineSkipped TextDiffViewLineType = "skipped"
+)
+
+type TextDiffViewLine struct {
+	Type TextDiffViewLineType `json:"type"`
+	Text string               `json:"text"`
+}
+
 func (v TextDiffView) String() string {
+	lines, err := v.Lines()
+	if err != nil {
+		return fmt.Sprintf("Error masking diff: %s", err)
+	}
+
+	var strs []string
+
+	for _, line := range lines {
+		switch line.Type {
+		case TextDiffViewLineAdded:
+			strs = append(strs, color.New(color.FgGreen).Sprint(line.Text))
+		case TextDiffViewLineRemoved:
+			strs = append(strs, color.New(color.FgRed).Sprint(line.Text))
+		default:
+			strs = append(strs, line.Text)
+		}
+	}
+
+	return strings.Join(strs, "\n") + "\n"
+}
+
+// Lines returns uncolored diff lines (with line numbers if configured)
+func (v TextDiffView) Lines() ([]TextDiffViewLine, error) {
 	var diffRecords []difflib.DiffRecord
 
 	if v.opts.Mask {
 		textDiff, err := v.diff.Masked(v.maskRules)
 		if err != nil {
-			return fmt.Sprintf("Error masking diff: %s", err)
+			return nil, err
 		}
 		diffRecords = textDiff.Records()
 	} else {
 		diffRecords = v.diff.Full().Records()
 	}
 
-	lines := []string{}
+	lines := []TextDiffViewLine{}
 	changedLines := map[int]struct{}{}
 
 	for lineNum, diff := range diffRecords {
@@ -66,29 +104,29 @@ func (v TextDiffView) String() string {
 	for lineNum, diff := range diffRecords {
 		switch diff.Delta {
 		case difflib.RightOnly:
-			lines = append(lines, color.New(color.FgGreen).Sprintf("%s+ %s",
-				lineNums(emptyLineStr, " ", lineNumStr(diff.LineRight)), diff.Payload))
+			lines = append(lines, TextDiffViewLine{TextDiffViewLineAdded, fmt.Sprintf("%s+ %s",
+				lineNums(emptyLineStr, " ", lineNumStr(diff.LineRight)), diff.Payload)})
 
 		case difflib.LeftOnly:
-			lines = append(lines, color.New(color.FgRed).Sprintf("%s- %s",
-				lineNums(lineNumStr(diff.LineLeft), " ", emptyLineStr), diff.Payload))
+			lines = append(lines, TextDiffViewLine{TextDiffViewLineRemoved, fmt.Sprintf("%s- %s",
+				lineNums(lineNumStr(diff.LineLeft), " ", emptyLineStr), diff.Payload)})
 
 		case difflib.Common:
 			newInContext := v.inContext(lineNum, changedLines)
 			if lineNum != 0 && !prevInContext && newInContext {
-				lines = append(lines, "  ...")
+				lines = append(lines, TextDiffViewLine{TextDiffViewLineSkipped, "  ..."})
 			}
 			if newInContext {
 				// LineLeft == LineRight
-				lines = append(lines, fmt.Sprintf("%s  %s",
+				lines = append(lines, TextDiffViewLine{TextDiffViewLineCommon, fmt.Sprintf("%s  %s",
 					lineNums(lineNumStr(diff.LineLeft), ",", lineNumStr(diff.LineRight)),
-					diff.Payload))
+					diff.Payload)})
 			}
 			prevInContext = newInContext
 		}
 	}
 
-	return strings.Join(lines, "\n") + "\n"
+	return lines, nil
 }
 
 func (v TextDiffView) inContext(lineNum int, changedLines map[int]struct{}) bool {
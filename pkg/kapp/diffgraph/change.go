@@ -137,6 +137,15 @@ func (c *Change) Groups() ([]ChangeGroup, error) {
 	return groups, nil
 }
 
+// Hook returns hook configuration if change creates a hook
+// (deleting hooks is not considered to be part of the hook lifecycle)
+func (c *Change) Hook() (ctlres.Hook, bool, error) {
+	if c.Change.Op() != ActualChangeOpUpsert {
+		return ctlres.Hook{}, false, nil
+	}
+	return ctlres.NewHook(c.Change.Resource())
+}
+
 func (c *Change) AllRules() ([]ChangeRule, error) {
 	if c.rules != nil {
 		return *c.rules, nil
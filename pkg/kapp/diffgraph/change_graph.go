@@ -45,6 +45,11 @@ func NewChangeGraph(changes []ActualChange,
 		return graph, fmt.Errorf("Change graph: Calculating required deps: %w", err)
 	}
 
+	err = graph.buildHookEdges()
+	if err != nil {
+		return graph, fmt.Errorf("Change graph: Calculating hook deps: %w", err)
+	}
+
 	err = graph.checkCycles()
 	if err != nil {
 		// Return graph for inspection
@@ -139,6 +144,40 @@ func (g *ChangeGraph) buildEdges(allowRule func(ChangeRule) bool,
 	return nil
 }
 
+// buildHookEdges makes all changes wait for pre hooks,
+// and post hooks wait for all other changes
+func (g *ChangeGraph) buildHookEdges() error {
+	defer g.logger.DebugFunc("buildHookEdges").Finish()
+
+	var preHooks, postHooks, others []*Change
+
+	for _, graphChange := range g.changes {
+		hook, isHook, err := graphChange.Hook()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case !isHook:
+			others = append(others, graphChange)
+		case hook.IsPre():
+			preHooks = append(preHooks, graphChange)
+		default:
+			postHooks = append(postHooks, graphChange)
+		}
+	}
+
+	for _, change := range others {
+		change.WaitingFor = append(change.WaitingFor, preHooks...)
+	}
+	for _, change := range postHooks {
+		change.WaitingFor = append(change.WaitingFor, preHooks...)
+		change.WaitingFor = append(change.WaitingFor, others...)
+	}
+
+	return nil
+}
+
 func (g *ChangeGraph) All() []*Change {
 	return g.AllMatching(func(_ *Change) bool { return true })
 }
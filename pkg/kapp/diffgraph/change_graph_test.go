@@ -836,6 +836,63 @@ type: kubernetes.io/service-account-token
 	require.Equal(t, expectedOutput, output)
 }
 
+func TestChangeGraphWithHooks(t *testing.T) {
+	configYAML := `
+kind: Job
+metadata:
+  name: post-deploy
+  annotations:
+    kapp.k14s.io/hook: post-apply
+---
+kind: ConfigMap
+metadata:
+  name: app-config
+---
+kind: Deployment
+metadata:
+  name: app
+---
+kind: Job
+metadata:
+  name: migrations
+  annotations:
+    kapp.k14s.io/hook: pre-apply
+`
+
+	graph, err := buildChangeGraph(configYAML, ctldgraph.ActualChangeOpUpsert, t)
+	require.NoErrorf(t, err, "Expected graph to build")
+
+	output := strings.TrimSpace(graph.PrintStr())
+	expectedOutput := strings.TrimSpace(`
+(upsert) job/post-deploy () cluster
+  (upsert) job/migrations () cluster
+  (upsert) configmap/app-config () cluster
+    (upsert) job/migrations () cluster
+  (upsert) deployment/app () cluster
+    (upsert) job/migrations () cluster
+(upsert) configmap/app-config () cluster
+  (upsert) job/migrations () cluster
+(upsert) deployment/app () cluster
+  (upsert) job/migrations () cluster
+(upsert) job/migrations () cluster
+`)
+	require.Equal(t, expectedOutput, output)
+}
+
+func TestChangeGraphWithInvalidHook(t *testing.T) {
+	configYAML := `
+kind: Job
+metadata:
+  name: migrations
+  annotations:
+    kapp.k14s.io/hook: pre-install
+`
+
+	_, err := buildChangeGraph(configYAML, ctldgraph.ActualChangeOpUpsert, t)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Expected annotation 'kapp.k14s.io/hook' on resource 'job/migrations () cluster' to have value")
+}
+
 func buildChangeGraph(resourcesBs string, op ctldgraph.ActualChangeOp, t *testing.T) (*ctldgraph.ChangeGraph, error) {
 	return buildChangeGraphWithOpts(buildGraphOpts{resourcesBs: resourcesBs, op: op}, t)
 }
@@ -13,11 +13,39 @@ const (
     <script src="/assets/all.js"></script>
    	<script>window.diffData = ` + IndexHTMLDiffDataJSONMarker + `;</script>
   </head>
-  <body>
-  	<h1>Changes</h1>
-  	<p>Changes are sorted in order that they will be applied. They will be applied in parallel within their group. Each change lists other changes that it will wait for before being applied.</p>
-    <ol id="deps"></ol>
-  </body>
+  <body>` + bodyHTML + `</body>
 </html>
 `
+
+	// bodyHTML is shared between diff UI and diff report
+	bodyHTML = `
+    <h1 id="title"></h1>
+
+    <div id="approval">
+      <button id="approve">Approve</button>
+      <button id="cancel">Cancel</button>
+      <span id="approval-status"></span>
+    </div>
+
+    <h2>Summary</h2>
+    <p id="filters">
+      Kind: <select id="filter-kind"></select>
+      Namespace: <select id="filter-namespace"></select>
+      Op: <select id="filter-op"></select>
+    </p>
+    <table id="summary">
+      <thead>
+        <tr><th>Namespace</th><th>Name</th><th>Kind</th><th>Version</th><th>Op</th><th>Op st.</th><th>Wait to</th></tr>
+      </thead>
+      <tbody></tbody>
+    </table>
+    <ul id="notes"></ul>
+
+    <h2>Diffs</h2>
+    <div id="diffs"></div>
+
+    <h2>Order</h2>
+    <p>Changes are sorted in order that they will be applied. They will be applied in parallel within their group. Each change lists other changes that it will wait for before being applied.</p>
+    <ol id="deps"></ol>
+  `
 )
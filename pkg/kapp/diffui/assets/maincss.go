@@ -17,5 +17,21 @@ a {
 
 .highlighted .highlighted { background: #b3e0f7; }
 .highlighted .highlighted-for { background: #f7b3b3; }
+
+table { border-collapse: collapse; }
+th, td { text-align: left; padding: 2px 12px 2px 0; }
+tr:hover td { background: #f4f4f4; }
+
+.diff { margin: 0 0 8px 0; }
+.diff summary { cursor: pointer; font-weight: bold; }
+.diff pre { margin: 4px 0 0 0; padding: 4px; background: #fafafa; overflow-x: auto; }
+.diff .added { color: #1a7f37; background: #e6ffec; }
+.diff .removed { color: #cf222e; background: #ffebe9; }
+.diff .skipped { color: #888; }
+
+.op-create { color: #1a7f37; }
+.op-delete { color: #cf222e; }
+
+.groups-and-rules { color: #888; padding-left: 16px; }
 `
 )
@@ -77,21 +77,145 @@ function DependencyGraph($graph, allChanges, linearizedChangeSections, blockedCh
     var $change = $("<li/>").attr("data-change-id", changeID);
     var change = changeByID[changeID];
     var children = change.waitingForIDs || [];
+    var $span = $("<span/>").text(change.name + " ");
 
     if (children.length > 0) {
-      $change.append("<span>"+change.name+" "+
-        "<a href='' class='expand'>(+"+children.length+")</a> "+
-        "<a href='' class='highlight'>^</a></span>");
+      $span.append($("<a href='' class='expand'/>").text("(+"+children.length+")"));
+      $span.append(" ");
+      $span.append($("<a href='' class='highlight'/>").text("^"));
     } else {
-      $change.append("<span>"+change.name+" (0)</span>");
+      $span.append("(0)");
     }
+
+    $change.append($span);
+    $change.append(buildGroupsAndRules(change));
     return $change;
   }
 
+  function buildGroupsAndRules(change) {
+    var $details = $("<div class='groups-and-rules'/>");
+    if ((change.groups || []).length > 0) {
+      $details.append($("<div/>").text("groups: " + change.groups.join(", ")));
+    }
+    if ((change.rules || []).length > 0) {
+      $details.append($("<div/>").text("rules: " + change.rules.join(", ")));
+    }
+    return $details;
+  }
+
+  return {};
+}
+
+function ChangesView($summary, $notes, $diffs, $filters, changes, notes) {
+  var filterNames = ["kind", "namespace", "op"];
+
+  for (var i in filterNames) {
+    var name = filterNames[i];
+    var values = {};
+    for (var j in changes) {
+      values[changes[j][name]] = true;
+    }
+    var $select = $("#filter-"+name, $filters);
+    $select.append($("<option value=''/>").text("(all)"));
+    var sortedValues = Object.keys(values).sort();
+    for (var j in sortedValues) {
+      $select.append($("<option/>").attr("value", sortedValues[j]).text(sortedValues[j] || "(none)"));
+    }
+  }
+
+  for (var i in changes) {
+    var change = changes[i];
+    var $row = $("<tr/>").attr("data-change-idx", i);
+    $row.append($("<td/>").text(change.namespace));
+    $row.append($("<td/>").append($("<a/>").attr("href", "#diff-"+i).text(change.name)));
+    $row.append($("<td/>").text(change.kind));
+    $row.append($("<td/>").text(change.version));
+    $row.append($("<td/>").addClass("op-"+change.op).text(change.op));
+    $row.append($("<td/>").text(change.opStrategy));
+    $row.append($("<td/>").text(change.waitOp));
+    $summary.append($row);
+  }
+
+  for (var i in notes) {
+    $notes.append($("<li/>").text(notes[i]));
+  }
+
+  for (var i in changes) {
+    var change = changes[i];
+    var $pre = $("<pre/>");
+    for (var j in change.diffLines) {
+      var line = change.diffLines[j];
+      $pre.append($("<div/>").addClass(line.type).text(line.text));
+    }
+    var $summaryLine = $("<summary/>").text("@@ " + change.op + " " + change.description + " @@");
+    $diffs.append($("<details open/>").addClass("diff").attr("id", "diff-"+i).attr("data-change-idx", i).append($summaryLine).append($pre));
+  }
+
+  $filters.on("change", "select", function() {
+    for (var i in changes) {
+      var matches = true;
+      for (var j in filterNames) {
+        var val = $("#filter-"+filterNames[j], $filters).val();
+        if (val && changes[i][filterNames[j]] != val) {
+          matches = false;
+        }
+      }
+      $("[data-change-idx=\""+i+"\"]", $summary).toggle(matches);
+      $("[data-change-idx=\""+i+"\"]", $diffs).toggle(matches);
+    }
+  });
+
+  return {};
+}
+
+function Approval($approval, approval) {
+  if (!approval) {
+    $approval.hide();
+    return {};
+  }
+
+  // Token is only available via URL printed to the terminal
+  var approvalToken = new URLSearchParams(window.location.hash.substring(1)).get("approvalToken");
+  if (!approvalToken) {
+    $("button", $approval).prop("disabled", true);
+    $("#approval-status", $approval).text("Open URL printed to the terminal to approve or cancel changes.");
+    return {};
+  }
+
+  function decide(path, status) {
+    $("button", $approval).prop("disabled", true);
+    $.ajax({
+      type: "POST",
+      url: path,
+      headers: {"X-Kapp-Approval-Token": approvalToken},
+    }).done(function() {
+      $("#approval-status", $approval).text(status);
+    }).fail(function(xhr) {
+      $("button", $approval).prop("disabled", false);
+      $("#approval-status", $approval).text("Error: " + xhr.responseText);
+    });
+  }
+
+  $("#approve", $approval).on("click", function() {
+    decide("/approve", "Approved. Changes are being applied (see terminal for progress).");
+  });
+  $("#cancel", $approval).on("click", function() {
+    decide("/cancel", "Cancelled. No changes were applied.");
+  });
+
   return {};
 }
 
 $(document).ready(function() {
+  $("#title").text(window.diffData.title || "Changes");
+
+  ChangesView($("#summary tbody"), $("#notes"), $("#diffs"), $("#filters"),
+    window.diffData.changes || [],
+    window.diffData.notes || [],
+  );
+
+  Approval($("#approval"), window.diffData.approval);
+
   DependencyGraph($("#deps"),
     window.diffData.allChanges || [],
     window.diffData.linearizedChangeSections || [],
@@ -0,0 +1,23 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package assets
+
+const (
+	ReportHTMLCSSMarker = "__report_css__"
+	ReportHTMLJSMarker  = "__report_js__"
+
+	// ReportHTML includes all assets so that it could be viewed without a server
+	ReportHTML = `<!DOCTYPE html>
+<html>
+  <head>
+    <meta charset="utf-8">
+    <title>kapp - diff report</title>
+    <style>` + ReportHTMLCSSMarker + `</style>
+    <script>window.diffData = ` + IndexHTMLDiffDataJSONMarker + `;</script>
+    <script>` + ReportHTMLJSMarker + `</script>
+  </head>
+  <body>` + bodyHTML + `</body>
+</html>
+`
+)
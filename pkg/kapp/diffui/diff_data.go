@@ -0,0 +1,76 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diffui
+
+import (
+	"fmt"
+
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+)
+
+type diffData struct {
+	AllChanges               []diffDataChange `json:"allChanges"`
+	LinearizedChangeSections [][]string       `json:"linearizedChangeSections"`
+	BlockedChanges           []string         `json:"blockedChanges"`
+}
+
+type diffDataChange struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	WaitingForIDs []string `json:"waitingForIDs"`
+
+	Groups []string `json:"groups"`
+	Rules  []string `json:"rules"`
+}
+
+func newDiffData(changesGraph *ctldgraph.ChangeGraph) diffData {
+	allChanges := changesGraph.All()
+	linearizedChangeSections, blockedChanges := changesGraph.Linearized()
+
+	diffData := diffData{}
+
+	for _, change := range allChanges {
+		ddChange := diffDataChange{ID: diffDataChangeID(change), Name: change.Description()}
+		for _, depChange := range change.WaitingFor {
+			ddChange.WaitingForIDs = append(ddChange.WaitingForIDs, diffDataChangeID(depChange))
+		}
+		ddChange.Groups, ddChange.Rules = diffDataChangeGroupsAndRules(change)
+		diffData.AllChanges = append(diffData.AllChanges, ddChange)
+	}
+
+	for _, section := range linearizedChangeSections {
+		var changeIDs []string
+		for _, change := range section {
+			changeIDs = append(changeIDs, diffDataChangeID(change))
+		}
+		diffData.LinearizedChangeSections = append(diffData.LinearizedChangeSections, changeIDs)
+	}
+
+	for _, change := range blockedChanges {
+		diffData.BlockedChanges = append(diffData.BlockedChanges, diffDataChangeID(change))
+	}
+
+	return diffData
+}
+
+// diffDataChangeGroupsAndRules ignores errors since groups and rules
+// were already successfully evaluated when graph was built
+func diffDataChangeGroupsAndRules(change *ctldgraph.Change) ([]string, []string) {
+	var groupNames, ruleStrs []string
+
+	groups, _ := change.Groups()
+	for _, group := range groups {
+		groupNames = append(groupNames, group.Name)
+	}
+
+	rules, _ := change.ApplicableRules()
+	for _, rule := range rules {
+		ruleStrs = append(ruleStrs, fmt.Sprintf("%s %s %s %s",
+			rule.Action, rule.Order, rule.TargetAction, rule.TargetGroup.Name))
+	}
+
+	return groupNames, ruleStrs
+}
+
+func diffDataChangeID(ch *ctldgraph.Change) string { return fmt.Sprintf("ch-%p", ch) }
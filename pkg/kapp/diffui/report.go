@@ -0,0 +1,116 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diffui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	ctldiff "carvel.dev/kapp/pkg/kapp/diff"
+	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
+	"carvel.dev/kapp/pkg/kapp/diffui/assets"
+)
+
+type ReportOpts struct {
+	Title string
+	Graph *ctldgraph.ChangeGraph
+
+	Changes []ReportChange
+	// Notes summarize changes (eg counts of operations)
+	Notes []string
+}
+
+type ReportChange struct {
+	// Change is used to find change within the graph
+	Change ctldgraph.ActualChange `json:"-"`
+
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	Version    string `json:"version"`
+	Op         string `json:"op"`
+	OpStrategy string `json:"opStrategy"`
+	WaitOp     string `json:"waitOp"`
+
+	Description string                     `json:"description"`
+	DiffLines   []ctldiff.TextDiffViewLine `json:"diffLines"`
+
+	// ID is populated based on graph
+	ID string `json:"id"`
+}
+
+// Report is a self-contained HTML page with the same information
+// as shown by Server, so that it could be shared as a file
+type Report struct {
+	opts ReportOpts
+}
+
+type reportData struct {
+	diffData
+
+	Title   string         `json:"title"`
+	Changes []ReportChange `json:"changes"`
+	Notes   []string       `json:"notes"`
+
+	// Approval is only set when changes are reviewed via Server to be approved
+	// (approval token is not included since page is served without authentication)
+	Approval bool `json:"approval,omitempty"`
+}
+
+func NewReport(opts ReportOpts) Report {
+	return Report{opts}
+}
+
+func (r Report) HTML() ([]byte, error) {
+	// JSON encoder escapes HTML characters, so it's safe to embed it in script tag
+	dataBs, err := json.Marshal(newReportData(r.opts))
+	if err != nil {
+		return nil, fmt.Errorf("Marshaling report data: %w", err)
+	}
+
+	content := strings.NewReplacer(
+		assets.ReportHTMLCSSMarker, assets.Files["assets/all.css"].Content,
+		assets.ReportHTMLJSMarker, assets.Files["assets/all.js"].Content,
+		assets.IndexHTMLDiffDataJSONMarker, string(dataBs),
+	).Replace(assets.ReportHTML)
+
+	return []byte(content), nil
+}
+
+func newReportData(opts ReportOpts) reportData {
+	data := reportData{
+		diffData: newDiffData(opts.Graph),
+		Title:    opts.Title,
+		Changes:  []ReportChange{},
+		Notes:    opts.Notes,
+	}
+
+	changeIDs := map[ctldgraph.ActualChange]string{}
+	for _, change := range opts.Graph.All() {
+		changeIDs[change.Change] = diffDataChangeID(change)
+	}
+
+	for _, change := range opts.Changes {
+		change.ID = changeIDs[change.Change]
+		data.Changes = append(data.Changes, change)
+	}
+
+	return data
+}
+
+func (r Report) WriteToFile(path string) error {
+	content, err := r.HTML()
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(path, content, 0600)
+	if err != nil {
+		return fmt.Errorf("Writing diff report: %w", err)
+	}
+
+	return nil
+}
@@ -4,91 +4,151 @@
 package diffui
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
-	ctldgraph "carvel.dev/kapp/pkg/kapp/diffgraph"
 	"carvel.dev/kapp/pkg/kapp/diffui/assets"
 	"github.com/cppforlife/go-cli-ui/ui"
 )
 
+const (
+	serverApprovalTokenHeader   = "X-Kapp-Approval-Token"
+	serverApprovalTokenFragment = "approvalToken"
+)
+
 type ServerOpts struct {
-	DiffDataFunc func() *ctldgraph.ChangeGraph
+	ReportOptsFunc func() ReportOpts
 }
 
 type Server struct {
 	opts ServerOpts
 	ui   ui.UI
+
+	// Set once server starts listening
+	allowedHosts map[string]struct{}
+
+	// Only set when server is used to approve changes
+	approvalToken string
+	decisionCh    chan bool
+	decisionOnce  sync.Once
 }
 
 func NewServer(opts ServerOpts, ui ui.UI) *Server {
-	return &Server{opts, ui}
+	return &Server{opts: opts, ui: ui}
 }
 
 func (s *Server) Mux() *http.ServeMux {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", s.noCacheHandler(s.mainHandler))
 	mux.HandleFunc("/assets/", s.noCacheHandler(s.assetHandler))
+	mux.HandleFunc("/approve", s.noCacheHandler(s.decisionHandler(true)))
+	mux.HandleFunc("/cancel", s.noCacheHandler(s.decisionHandler(false)))
 	return mux
 }
 
+// Run serves changes until process is stopped
 func (s *Server) Run() error {
-	listener, err := net.Listen("tcp", "localhost:0")
+	listener, err := s.listen()
 	if err != nil {
 		return err
 	}
 
 	s.ui.BeginLinef("Diff UI server: http://%s\n", listener.Addr())
 
-	return (&http.Server{Handler: s.Mux()}).Serve(listener)
+	return (&http.Server{Handler: s.hostCheckHandler(s.Mux())}).Serve(listener)
 }
 
-type diffData struct {
-	AllChanges               []diffDataChange `json:"allChanges"`
-	LinearizedChangeSections [][]string       `json:"linearizedChangeSections"`
-	BlockedChanges           []string         `json:"blockedChanges"`
-}
+// RunForApproval serves changes until they are either approved or cancelled
+// via UI; returns error if changes were cancelled
+func (s *Server) RunForApproval() error {
+	tokenBs := make([]byte, 16)
 
-type diffDataChange struct {
-	ID            string   `json:"id"`
-	Name          string   `json:"name"`
-	WaitingForIDs []string `json:"waitingForIDs"`
-}
+	_, err := rand.Read(tokenBs)
+	if err != nil {
+		return fmt.Errorf("Generating approval token: %w", err)
+	}
 
-func (s *Server) mainHandler(w http.ResponseWriter, _ *http.Request) {
-	changesGraph := s.opts.DiffDataFunc()
+	s.approvalToken = hex.EncodeToString(tokenBs)
+	s.decisionCh = make(chan bool, 1)
+
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+
+	// Token is only shared via URL fragment (which is not sent to the server)
+	// so that page itself does not need to include it
+	s.ui.BeginLinef("Diff UI server: http://%s/#%s=%s\n", listener.Addr(), serverApprovalTokenFragment, s.approvalToken)
+	s.ui.PrintLinef("Waiting for changes to be approved or cancelled in diff UI")
 
-	allChanges := changesGraph.All()
-	linearizedChangeSections, blockedChanges := changesGraph.Linearized()
-	changeID := func(ch *ctldgraph.Change) string { return fmt.Sprintf("ch-%p", ch) }
+	server := &http.Server{Handler: s.hostCheckHandler(s.Mux())}
+	serveErrCh := make(chan error, 1)
 
-	diffData := diffData{}
+	go func() { serveErrCh <- server.Serve(listener) }()
 
-	for _, change := range allChanges {
-		ddChange := diffDataChange{ID: changeID(change), Name: change.Description()}
-		for _, depChange := range change.WaitingFor {
-			ddChange.WaitingForIDs = append(ddChange.WaitingForIDs, changeID(depChange))
+	select {
+	case approved := <-s.decisionCh:
+		server.Shutdown(context.Background())
+		if !approved {
+			return fmt.Errorf("Changes were cancelled in diff UI")
 		}
-		diffData.AllChanges = append(diffData.AllChanges, ddChange)
+		return nil
+
+	case err := <-serveErrCh:
+		return err
 	}
+}
 
-	for _, section := range linearizedChangeSections {
-		var changeIDs []string
-		for _, change := range section {
-			changeIDs = append(changeIDs, changeID(change))
-		}
-		diffData.LinearizedChangeSections = append(diffData.LinearizedChangeSections, changeIDs)
+func (s *Server) listen() (net.Listener, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
 	}
 
-	for _, change := range blockedChanges {
-		diffData.BlockedChanges = append(diffData.BlockedChanges, changeID(change))
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		listener.Close()
+		return nil, err
 	}
 
-	dataBs, _ := json.Marshal(diffData)
+	s.allowedHosts = map[string]struct{}{
+		net.JoinHostPort("localhost", port): {},
+		net.JoinHostPort("127.0.0.1", port): {},
+	}
+
+	return listener, nil
+}
+
+// hostCheckHandler rejects requests made via other host names
+// so that other sites cannot read changes via DNS rebinding
+func (s *Server) hostCheckHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, found := s.allowedHosts[r.Host]; !found {
+			http.Error(w, "Expected request to be made to localhost", http.StatusForbidden)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) mainHandler(w http.ResponseWriter, _ *http.Request) {
+	data := newReportData(s.opts.ReportOptsFunc())
+	data.Approval = s.decisionCh != nil
+
+	dataBs, err := json.Marshal(data)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Marshaling diff data: %s", err), http.StatusInternalServerError)
+		return
+	}
 
 	indexHTML := assets.Files[assets.IndexHTMLPath].Content
 	content := strings.ReplaceAll(indexHTML, assets.IndexHTMLDiffDataJSONMarker, string(dataBs))
@@ -96,6 +156,39 @@ func (s *Server) mainHandler(w http.ResponseWriter, _ *http.Request) {
 	s.write(w, []byte(content))
 }
 
+// decisionHandler requires token printed to the terminal so that
+// other sites cannot approve changes on user's behalf
+func (s *Server) decisionHandler(approved bool) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.decisionCh == nil {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			http.Error(w, "Expected POST request", http.StatusMethodNotAllowed)
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(serverApprovalTokenHeader)), []byte(s.approvalToken)) != 1 {
+			http.Error(w, "Expected valid approval token", http.StatusForbidden)
+			return
+		}
+
+		var decided bool
+
+		s.decisionOnce.Do(func() {
+			s.decisionCh <- approved
+			decided = true
+		})
+
+		if !decided {
+			http.Error(w, "Changes were already approved or cancelled", http.StatusConflict)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
 func (s *Server) assetHandler(w http.ResponseWriter, r *http.Request) {
 	if strings.HasSuffix(r.URL.Path, ".css") {
 		w.Header().Set("Content-Type", "text/css")
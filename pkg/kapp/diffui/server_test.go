@@ -0,0 +1,100 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diffui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerHostCheck(t *testing.T) {
+	server := NewServer(ServerOpts{}, ui.NewNoopUI())
+	server.allowedHosts = map[string]struct{}{"localhost:1234": {}, "127.0.0.1:1234": {}}
+
+	handler := server.hostCheckHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for host, expectedStatus := range map[string]int{
+		"localhost:1234":    http.StatusOK,
+		"127.0.0.1:1234":    http.StatusOK,
+		"localhost:4321":    http.StatusForbidden,
+		"attacker.com:1234": http.StatusForbidden,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Host = host
+
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		require.Equal(t, expectedStatus, resp.Code, "Unexpected status for host %s", host)
+	}
+}
+
+func TestServerDecisionRequiresToken(t *testing.T) {
+	server := newTestApprovalServer()
+
+	for _, tc := range []struct {
+		Method         string
+		Token          string
+		ExpectedStatus int
+	}{
+		{http.MethodGet, "token", http.StatusMethodNotAllowed},
+		{http.MethodPost, "", http.StatusForbidden},
+		{http.MethodPost, "other", http.StatusForbidden},
+		{http.MethodPost, "tokentoken", http.StatusForbidden},
+	} {
+		resp := server.decide(tc.Method, "/approve", tc.Token)
+		require.Equal(t, tc.ExpectedStatus, resp.Code, "Unexpected status for %s with token '%s'", tc.Method, tc.Token)
+	}
+
+	require.Len(t, server.decisionCh, 0, "Expected no decision to be made")
+}
+
+func TestServerDecisionIsOnlyMadeOnce(t *testing.T) {
+	server := newTestApprovalServer()
+
+	resp := server.decide(http.MethodPost, "/cancel", "token")
+	require.Equal(t, http.StatusNoContent, resp.Code)
+
+	resp = server.decide(http.MethodPost, "/approve", "token")
+	require.Equal(t, http.StatusConflict, resp.Code)
+
+	require.Len(t, server.decisionCh, 1)
+	require.False(t, <-server.decisionCh, "Expected first decision (cancel) to be kept")
+}
+
+func TestServerDecisionIsNotAvailableWithoutApproval(t *testing.T) {
+	server := testServer{NewServer(ServerOpts{}, ui.NewNoopUI())}
+
+	resp := server.decide(http.MethodPost, "/approve", "")
+	require.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+type testServer struct {
+	*Server
+}
+
+func newTestApprovalServer() testServer {
+	server := NewServer(ServerOpts{}, ui.NewNoopUI())
+	server.approvalToken = "token"
+	server.decisionCh = make(chan bool, 1)
+	return testServer{server}
+}
+
+func (s testServer) decide(method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if len(token) > 0 {
+		req.Header.Set(serverApprovalTokenHeader, token)
+	}
+
+	resp := httptest.NewRecorder()
+	s.Mux().ServeHTTP(resp, req)
+
+	return resp
+}
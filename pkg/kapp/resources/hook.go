@@ -0,0 +1,96 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"fmt"
+)
+
+const (
+	HookAnnKey             = "kapp.k14s.io/hook"               // valid values: pre-apply, post-apply, pre-delete, post-delete
+	HookDeletePolicyAnnKey = "kapp.k14s.io/hook-delete-policy" // valid values: before-hook-creation (default), hook-succeeded
+)
+
+type HookType string
+
+const (
+	HookTypePreApply   HookType = "pre-apply"
+	HookTypePostApply  HookType = "post-apply"
+	HookTypePreDelete  HookType = "pre-delete"
+	HookTypePostDelete HookType = "post-delete"
+)
+
+type HookDeletePolicy string
+
+const (
+	HookDeletePolicyBeforeHookCreation HookDeletePolicy = "before-hook-creation"
+	HookDeletePolicyHookSucceeded      HookDeletePolicy = "hook-succeeded"
+)
+
+// Hook is a resource (typically a Job) that is created every time
+// app is deployed (or deleted) instead of being diffed against
+// its existing copy. Hooks are applied before or after all other changes.
+type Hook struct {
+	Type         HookType
+	DeletePolicy HookDeletePolicy
+}
+
+// NewHook returns hook configuration of a resource; returns false if resource is not a hook.
+func NewHook(res Resource) (Hook, bool, error) {
+	hookType, found := res.Annotations()[HookAnnKey]
+	if !found {
+		return Hook{}, false, nil
+	}
+
+	hook := Hook{
+		Type:         HookType(hookType),
+		DeletePolicy: HookDeletePolicyBeforeHookCreation,
+	}
+
+	switch hook.Type {
+	case HookTypePreApply, HookTypePostApply, HookTypePreDelete, HookTypePostDelete:
+	default:
+		return Hook{}, false, fmt.Errorf("Expected annotation '%s' on resource '%s' to have value "+
+			"'pre-apply', 'post-apply', 'pre-delete' or 'post-delete', but was '%s'", HookAnnKey, res.Description(), hookType)
+	}
+
+	if policy, found := res.Annotations()[HookDeletePolicyAnnKey]; found {
+		hook.DeletePolicy = HookDeletePolicy(policy)
+	}
+
+	switch hook.DeletePolicy {
+	case HookDeletePolicyBeforeHookCreation, HookDeletePolicyHookSucceeded:
+	default:
+		return Hook{}, false, fmt.Errorf("Expected annotation '%s' on resource '%s' to have value "+
+			"'before-hook-creation' or 'hook-succeeded', but was '%s'", HookDeletePolicyAnnKey, res.Description(), hook.DeletePolicy)
+	}
+
+	return hook, true, nil
+}
+
+func (h Hook) IsPre() bool { return h.Type == HookTypePreApply || h.Type == HookTypePreDelete }
+
+func (h Hook) IsDeletePhase() bool {
+	return h.Type == HookTypePreDelete || h.Type == HookTypePostDelete
+}
+
+// SplitDeleteHooks splits resources into ones that should be applied
+// during deploy (including apply hooks) and delete hooks
+func SplitDeleteHooks(resources []Resource) ([]Resource, []Resource, error) {
+	var applyResources, deleteHookResources []Resource
+
+	for _, res := range resources {
+		hook, isHook, err := NewHook(res)
+		if err != nil {
+			return nil, nil, err
+		}
+		if isHook && hook.IsDeletePhase() {
+			deleteHookResources = append(deleteHookResources, res)
+		} else {
+			applyResources = append(applyResources, res)
+		}
+	}
+
+	return applyResources, deleteHookResources, nil
+}
@@ -4,6 +4,7 @@
 package resources
 
 import (
+	"context"
 	"fmt"
 
 	"carvel.dev/kapp/pkg/kapp/logger"
@@ -26,9 +27,28 @@ func NewIdentifiedResources(coreClient kubernetes.Interface, resourceTypes Resou
 		fallbackAllowedNamespaces, logger.NewPrefixed("IdentifiedResources")}
 }
 
+// WithContext returns a copy that makes API requests with given context
+// (if underlying resources support it)
+func (r IdentifiedResources) WithContext(ctx context.Context) IdentifiedResources {
+	if resources, ok := r.resources.(ResourcesWithContext); ok {
+		r.resources = resources.WithContext(ctx)
+	}
+	return r
+}
+
 func (r IdentifiedResources) Create(resource Resource) (Resource, error) {
 	defer r.logger.DebugFunc(fmt.Sprintf("Create(%s)", resource.Description())).Finish()
+	return r.create(resource, CreateOpts{})
+}
 
+// CreateDryRun returns resource as it would have been created
+// (including mutations made by admission webhooks) without persisting it.
+func (r IdentifiedResources) CreateDryRun(resource Resource) (Resource, error) {
+	defer r.logger.DebugFunc(fmt.Sprintf("CreateDryRun(%s)", resource.Description())).Finish()
+	return r.create(resource, CreateOpts{DryRun: true})
+}
+
+func (r IdentifiedResources) create(resource Resource, opts CreateOpts) (Resource, error) {
 	resource = resource.DeepCopy()
 
 	err := NewIdentityAnnotation(resource).AddMod().Apply(resource)
@@ -36,7 +56,7 @@ func (r IdentifiedResources) Create(resource Resource) (Resource, error) {
 		return nil, err
 	}
 
-	resource, err = r.resources.Create(resource)
+	resource, err = r.resources.Create(resource, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -51,7 +71,17 @@ func (r IdentifiedResources) Create(resource Resource) (Resource, error) {
 
 func (r IdentifiedResources) Update(resource Resource) (Resource, error) {
 	defer r.logger.DebugFunc(fmt.Sprintf("Update(%s)", resource.Description())).Finish()
+	return r.update(resource, UpdateOpts{})
+}
+
+// UpdateDryRun returns resource as it would have been updated
+// (including mutations made by admission webhooks) without persisting it.
+func (r IdentifiedResources) UpdateDryRun(resource Resource) (Resource, error) {
+	defer r.logger.DebugFunc(fmt.Sprintf("UpdateDryRun(%s)", resource.Description())).Finish()
+	return r.update(resource, UpdateOpts{DryRun: true})
+}
 
+func (r IdentifiedResources) update(resource Resource, opts UpdateOpts) (Resource, error) {
 	resource = resource.DeepCopy()
 
 	err := NewIdentityAnnotation(resource).AddMod().Apply(resource)
@@ -59,7 +89,7 @@ func (r IdentifiedResources) Update(resource Resource) (Resource, error) {
 		return nil, err
 	}
 
-	resource, err = r.resources.Update(resource)
+	resource, err = r.resources.Update(resource, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -72,9 +102,39 @@ func (r IdentifiedResources) Update(resource Resource) (Resource, error) {
 	return resource, nil
 }
 
-func (r IdentifiedResources) Patch(resource Resource, patchType types.PatchType, data []byte) (Resource, error) {
+func (r IdentifiedResources) Patch(resource Resource, patchType types.PatchType, data []byte, opts PatchOpts) (Resource, error) {
 	defer r.logger.DebugFunc(fmt.Sprintf("Patch(%s)", resource.Description())).Finish()
-	return r.resources.Patch(resource, patchType, data)
+	return r.resources.Patch(resource, patchType, data, opts)
+}
+
+// ServerSideApply sends resource as an apply patch, hence
+// only fields present in the resource become owned by the field manager.
+func (r IdentifiedResources) ServerSideApply(resource Resource, opts PatchOpts) (Resource, error) {
+	defer r.logger.DebugFunc(fmt.Sprintf("ServerSideApply(%s)", resource.Description())).Finish()
+
+	resource = resource.DeepCopy()
+
+	err := NewIdentityAnnotation(resource).AddMod().Apply(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := resource.AsCompactBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err = r.resources.Patch(resource, types.ApplyPatchType, data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	err = NewIdentityAnnotation(resource).RemoveMod().Apply(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return resource, nil
 }
 
 func (r IdentifiedResources) Delete(resource Resource) error {
@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
 )
 
 func TestIdentifiedResourcesListReturnsLabeledResources(t *testing.T) {
@@ -61,11 +62,18 @@ func (r *FakeResources) Exists(ctlres.Resource, ctlres.ExistsOpts) (ctlres.Resou
 	return nil, true, nil
 }
 func (r *FakeResources) Get(ctlres.Resource) (ctlres.Resource, error) { return nil, nil }
-func (r *FakeResources) Patch(ctlres.Resource, types.PatchType, []byte) (ctlres.Resource, error) {
+func (r *FakeResources) Patch(ctlres.Resource, types.PatchType, []byte, ctlres.PatchOpts) (ctlres.Resource, error) {
+	return nil, nil
+}
+func (r *FakeResources) Update(ctlres.Resource, ctlres.UpdateOpts) (ctlres.Resource, error) {
+	return nil, nil
+}
+func (r *FakeResources) Create(ctlres.Resource, ctlres.CreateOpts) (ctlres.Resource, error) {
+	return nil, nil
+}
+func (r *FakeResources) Watch(ctlres.ResourceType, ctlres.WatchOpts) (watch.Interface, error) {
 	return nil, nil
 }
-func (r *FakeResources) Update(ctlres.Resource) (ctlres.Resource, error) { return nil, nil }
-func (r *FakeResources) Create(ctlres.Resource) (ctlres.Resource, error) { return nil, nil }
 
 type FakeResourceTypes struct{}
 
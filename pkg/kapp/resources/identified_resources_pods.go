@@ -4,6 +4,7 @@
 package resources
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -14,8 +15,8 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
-func (r IdentifiedResources) PodResources(labelSelector labels.Selector, resourceNamespaces []string) UniquePodWatcher {
-	return UniquePodWatcher{labelSelector, uniqAndValidNamespaces(append(r.fallbackAllowedNamespaces, resourceNamespaces...)), r.coreClient}
+func (r IdentifiedResources) PodResources(ctx context.Context, labelSelector labels.Selector, resourceNamespaces []string) UniquePodWatcher {
+	return UniquePodWatcher{ctx, labelSelector, uniqAndValidNamespaces(append(r.fallbackAllowedNamespaces, resourceNamespaces...)), r.coreClient}
 }
 
 type PodWatcherI interface {
@@ -23,6 +24,7 @@ type PodWatcherI interface {
 }
 
 type UniquePodWatcher struct {
+	ctx                       context.Context
 	labelSelector             labels.Selector
 	fallbackAllowedNamespaces []string
 	coreClient                kubernetes.Interface
@@ -42,6 +44,7 @@ func (w UniquePodWatcher) Watch(podsToWatchCh chan corev1.Pod, cancelCh chan str
 
 		for _, namespace := range namespaces {
 			podWatcher := NewPodWatcher(
+				w.ctx,
 				w.coreClient.CoreV1().Pods(namespace),
 				metav1.ListOptions{LabelSelector: w.labelSelector.String()},
 			)
@@ -0,0 +1,179 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// Watch starts watching resources of given group kinds scoped to label selector.
+// Group kinds that cannot be watched (e.g. watch verb is not supported
+// or watching is forbidden) are not reported as watched, hence callers
+// are expected to fall back to polling for them.
+func (r IdentifiedResources) Watch(labelSelector labels.Selector, gks []schema.GroupKind,
+	resourceNamespaces []string) (*ResourcesWatch, error) {
+
+	defer r.logger.DebugFunc("Watch").Finish()
+
+	resTypes, err := r.resourceTypes.All(false)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &ResourcesWatch{
+		resources:  r.resources,
+		listOpts:   metav1.ListOptions{LabelSelector: labelSelector.String()},
+		namespaces: uniqAndValidNamespaces(append(r.fallbackAllowedNamespaces, resourceNamespaces...)),
+		watchedGKs: map[schema.GroupKind]struct{}{},
+		eventsCh:   make(chan Resource),
+		stopCh:     make(chan struct{}),
+	}
+
+	for _, resType := range MatchingAnyGK(resTypes, gks) {
+		if !resType.Watchable() {
+			continue
+		}
+		// Resource types include all served versions;
+		// watching one of them is enough to observe changes
+		gk := schema.GroupKind{Group: resType.APIResource.Group, Kind: resType.APIResource.Kind}
+		if _, found := w.watchedGKs[gk]; found {
+			continue
+		}
+		if w.start(resType) {
+			w.watchedGKs[gk] = struct{}{}
+		} else {
+			r.logger.Debug("Falling back to polling for %s", gk)
+		}
+	}
+
+	return w, nil
+}
+
+// ResourcesWatch reports resources as they are added, modified or deleted.
+type ResourcesWatch struct {
+	resources  Resources
+	listOpts   metav1.ListOptions
+	namespaces []string
+
+	watchedGKs map[schema.GroupKind]struct{}
+
+	eventsCh chan Resource
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+func (w *ResourcesWatch) Events() <-chan Resource { return w.eventsCh }
+
+func (w *ResourcesWatch) IsWatched(gk schema.GroupKind) bool {
+	_, found := w.watchedGKs[gk]
+	return found
+}
+
+func (w *ResourcesWatch) Stop() {
+	w.stopOnce.Do(func() { close(w.stopCh) })
+}
+
+func (w *ResourcesWatch) start(resType ResourceType) bool {
+	// Watch in all namespaces first and fallback to
+	// allowed namespaces if lack of permission
+	namespaceSets := [][]string{{""}}
+	if resType.Namespaced() && len(w.namespaces) > 0 {
+		namespaceSets = append(namespaceSets, w.namespaces)
+	}
+
+	for _, namespaces := range namespaceSets {
+		var watchers []watch.Interface
+		var err error
+
+		for _, ns := range namespaces {
+			var watcher watch.Interface
+
+			watcher, err = w.resources.Watch(resType, WatchOpts{Namespace: ns, ListOpts: w.listOpts})
+			if err != nil {
+				break
+			}
+			watchers = append(watchers, watcher)
+		}
+
+		if err == nil {
+			for i, watcher := range watchers {
+				go w.forward(resType, namespaces[i], watcher)
+			}
+			return true
+		}
+
+		for _, watcher := range watchers {
+			watcher.Stop()
+		}
+		if !errors.IsForbidden(err) {
+			return false
+		}
+	}
+
+	return false
+}
+
+func (w *ResourcesWatch) forward(resType ResourceType, namespace string, watcher watch.Interface) {
+	for {
+		closed := w.forwardUntilClosed(resType, watcher)
+		watcher.Stop()
+
+		if !closed {
+			return
+		}
+
+		// Watches may expire, hence try to re-establish;
+		// if that's not possible, periodic checks still pick up changes
+		select {
+		case <-time.After(time.Second):
+		case <-w.stopCh:
+			return
+		}
+
+		var err error
+
+		watcher, err = w.resources.Watch(resType, WatchOpts{Namespace: namespace, ListOpts: w.listOpts})
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (w *ResourcesWatch) forwardUntilClosed(resType ResourceType, watcher watch.Interface) bool {
+	for {
+		select {
+		case e, ok := <-watcher.ResultChan():
+			if !ok {
+				return true
+			}
+
+			switch e.Type {
+			case watch.Added, watch.Modified, watch.Deleted:
+				obj, ok := e.Object.(*unstructured.Unstructured)
+				if !ok {
+					continue
+				}
+				select {
+				case w.eventsCh <- NewResourceUnstructured(*obj, resType):
+				case <-w.stopCh:
+					return false
+				}
+
+			case watch.Error:
+				return true
+			}
+
+		case <-w.stopCh:
+			return false
+		}
+	}
+}
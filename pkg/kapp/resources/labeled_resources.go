@@ -95,6 +95,7 @@ type AllAndMatchingOpts struct {
 	ExistingNonLabeledResourcesCheckConcurrency int
 	SkipResourceOwnershipCheck                  bool
 	SkipOwnershipCheckAllowedApps               []string
+	AdoptionRules                               []AdoptionRule
 	IsNewApp                                    bool
 
 	DisallowedResourcesByLabelKeys []string
@@ -104,6 +105,23 @@ type AllAndMatchingOpts struct {
 	IdentifiedResourcesListOpts IdentifiedResourcesListOpts
 }
 
+// AdoptionRule allows resource associated with another app to be adopted.
+// Nil ResourceMatcher matches all resources; empty Apps matches all apps.
+type AdoptionRule struct {
+	ResourceMatcher ResourceMatcher
+	Apps            []string
+}
+
+func (r AdoptionRule) allows(res Resource, appName string) bool {
+	if r.ResourceMatcher != nil && !r.ResourceMatcher.Matches(res) {
+		return false
+	}
+	if len(r.Apps) > 0 && !slices.Contains(r.Apps, appName) {
+		return false
+	}
+	return true
+}
+
 // AllAndMatching returns set of all labeled resources
 // plus resources that match newResources.
 // Returns errors if non-labeled resources were labeled
@@ -185,7 +203,7 @@ func (a *LabeledResources) checkResourceOwnership(resources []Resource, opts All
 	var errs []error
 	labelValAppMap := map[string]string{}
 	isSelectiveOwnershipOverride := len(opts.SkipOwnershipCheckAllowedApps) > 0
-	if isSelectiveOwnershipOverride {
+	if isSelectiveOwnershipOverride || len(opts.AdoptionRules) > 0 {
 		labelValAppMap = opts.LabelValAppMapResolverFunc()
 	}
 
@@ -197,6 +215,9 @@ func (a *LabeledResources) checkResourceOwnership(resources []Resource, opts All
 				ownershipOverrideAllowed = a.ownershipOverrideAllowed(labelValAppMap, res,
 					expectedLabelKey, opts.SkipOwnershipCheckAllowedApps)
 			}
+			if !ownershipOverrideAllowed {
+				ownershipOverrideAllowed = a.adoptionAllowed(labelValAppMap[val], res, opts.AdoptionRules)
+			}
 			if val != expectedLabelVal && !ownershipOverrideAllowed {
 				ownerMsg := fmt.Sprintf("different label '%s=%s'", expectedLabelKey, val)
 				if opts.LabelErrorResolutionFunc != nil {
@@ -235,6 +256,15 @@ func (a *LabeledResources) ownershipOverrideAllowed(labelValAppMap map[string]st
 	return slices.Contains(overrideAllowedApps, appName)
 }
 
+func (a *LabeledResources) adoptionAllowed(appName string, res Resource, rules []AdoptionRule) bool {
+	for _, rule := range rules {
+		if rule.allows(res, appName) {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *LabeledResources) checkDisallowedLabels(resources []Resource, disallowedLblKeys []string) error {
 	var errs []error
 
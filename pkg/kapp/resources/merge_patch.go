@@ -0,0 +1,102 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// NewThreeWayMergePatch returns patch that changes current resource to match
+// modified resource and removes fields that were present in original resource
+// (ie last applied) but are no longer present in modified resource.
+// Fields that are only present in current resource (e.g. set by other controllers)
+// are left untouched. Strategic merge patch is used for built-in types
+// and JSON merge patch for other types (e.g. custom resources).
+// Original resource may be nil if it is not known.
+func NewThreeWayMergePatch(original, modified, current Resource) (types.PatchType, []byte, error) {
+	originalObj := map[string]interface{}{}
+	if original != nil {
+		originalObj = original.DeepCopyRaw()
+	}
+
+	obj, err := scheme.Scheme.New(current.GroupVersion().WithKind(current.Kind()))
+	if err != nil {
+		patch := newThreeWayJSONMergePatch(originalObj, modified.DeepCopyRaw(), current.DeepCopyRaw())
+		patchBytes, err := json.Marshal(patch)
+		if err != nil {
+			return "", nil, err
+		}
+		return types.MergePatchType, patchBytes, nil
+	}
+
+	lookupPatchMeta, err := strategicpatch.NewPatchMetaFromStruct(obj)
+	if err != nil {
+		return "", nil, fmt.Errorf("Building patch metadata for %s: %w", current.Description(), err)
+	}
+
+	var jsonBytes [][]byte
+
+	for _, obj := range []map[string]interface{}{originalObj, modified.DeepCopyRaw(), current.DeepCopyRaw()} {
+		bs, err := json.Marshal(obj)
+		if err != nil {
+			return "", nil, err
+		}
+		jsonBytes = append(jsonBytes, bs)
+	}
+
+	patchBytes, err := strategicpatch.CreateThreeWayMergePatch(
+		jsonBytes[0], jsonBytes[1], jsonBytes[2], lookupPatchMeta, true)
+	if err != nil {
+		return "", nil, fmt.Errorf("Calculating strategic merge patch for %s: %w", current.Description(), err)
+	}
+
+	return types.StrategicMergePatchType, patchBytes, nil
+}
+
+// newThreeWayJSONMergePatch follows RFC 7386: maps are merged recursively,
+// while other values (including lists) are replaced as a whole
+func newThreeWayJSONMergePatch(original, modified, current map[string]interface{}) map[string]interface{} {
+	patch := map[string]interface{}{}
+
+	for key, modifiedVal := range modified {
+		currentVal, found := current[key]
+		if !found {
+			patch[key] = modifiedVal
+			continue
+		}
+
+		typedModifiedVal, modifiedIsMap := modifiedVal.(map[string]interface{})
+		typedCurrentVal, currentIsMap := currentVal.(map[string]interface{})
+
+		if modifiedIsMap && currentIsMap {
+			typedOriginalVal, _ := original[key].(map[string]interface{})
+			subPatch := newThreeWayJSONMergePatch(typedOriginalVal, typedModifiedVal, typedCurrentVal)
+			if len(subPatch) > 0 {
+				patch[key] = subPatch
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(modifiedVal, currentVal) {
+			patch[key] = modifiedVal
+		}
+	}
+
+	for key := range original {
+		if _, found := modified[key]; found {
+			continue
+		}
+		if _, found := current[key]; found {
+			patch[key] = nil
+		}
+	}
+
+	return patch
+}
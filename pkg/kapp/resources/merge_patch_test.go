@@ -0,0 +1,135 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestNewThreeWayMergePatch_BuiltInType(t *testing.T) {
+	original := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    kept: ""
+    removed: ""
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1
+`))
+
+	modified := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    kept: ""
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:2
+`))
+
+	current := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    kept: ""
+    removed: ""
+    set-by-controller: ""
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1
+      - name: injected-sidecar
+        image: sidecar:1
+`))
+
+	patchType, patch, err := ctlres.NewThreeWayMergePatch(original, modified, current)
+	require.NoError(t, err)
+	require.Equal(t, types.StrategicMergePatchType, patchType)
+	require.JSONEq(t, `{"metadata":{"labels":{"removed":null}},`+
+		`"spec":{"template":{"spec":{"$setElementOrder/containers":[{"name":"app"}],"containers":[{"image":"app:2","name":"app"}]}}}}`, string(patch))
+}
+
+func TestNewThreeWayMergePatch_CustomType(t *testing.T) {
+	original := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+spec:
+  size: 1
+  color: red
+`))
+
+	modified := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+spec:
+  size: 2
+  items: [a, b]
+`))
+
+	current := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+spec:
+  size: 1
+  color: red
+  items: [a]
+  defaulted: true
+`))
+
+	patchType, patch, err := ctlres.NewThreeWayMergePatch(original, modified, current)
+	require.NoError(t, err)
+	require.Equal(t, types.MergePatchType, patchType)
+	require.JSONEq(t, `{"spec":{"size":2,"color":null,"items":["a","b"]}}`, string(patch))
+}
+
+func TestNewThreeWayMergePatch_WithoutOriginal(t *testing.T) {
+	modified := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+spec:
+  size: 2
+`))
+
+	current := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+spec:
+  size: 1
+  color: red
+`))
+
+	_, patch, err := ctlres.NewThreeWayMergePatch(nil, modified, current)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"spec":{"size":2}}`, string(patch))
+}
@@ -11,6 +11,10 @@ type ObjectRefSetMod struct {
 	ResourceMatcher ResourceMatcher
 	Path            Path
 	ReplacementFunc func(map[string]interface{}) error
+
+	// PathReplacementFunc is used instead of ReplacementFunc when replacement
+	// depends on location of the object (path includes concrete array indexes)
+	PathReplacementFunc func(map[string]interface{}, Path) error
 }
 
 var _ ResourceMod = ObjectRefSetMod{}
@@ -19,14 +23,14 @@ func (t ObjectRefSetMod) Apply(res Resource) error {
 	if !t.ResourceMatcher.Matches(res) {
 		return nil
 	}
-	err := t.apply(res.unstructured().Object, t.Path)
+	err := t.apply(res.unstructured().Object, t.Path, nil)
 	if err != nil {
 		return fmt.Errorf("ObjectRefSetMod for path '%s' on resource '%s': %w", t.Path.AsString(), res.Description(), err)
 	}
 	return nil
 }
 
-func (t ObjectRefSetMod) apply(obj interface{}, path Path) error {
+func (t ObjectRefSetMod) apply(obj interface{}, path Path, visitedPath Path) error {
 	for i, part := range path {
 		switch {
 		case part.MapKey != nil:
@@ -40,6 +44,7 @@ func (t ObjectRefSetMod) apply(obj interface{}, path Path) error {
 			if !found {
 				return nil
 			}
+			visitedPath = append(visitedPath, NewPathPartFromString(*part.MapKey))
 
 		case part.ArrayIndex != nil:
 			switch {
@@ -52,8 +57,8 @@ func (t ObjectRefSetMod) apply(obj interface{}, path Path) error {
 					return fmt.Errorf("Unexpected non-array found: %T", obj)
 				}
 
-				for _, obj := range typedObj {
-					err := t.apply(obj, path[i+1:])
+				for idx, obj := range typedObj {
+					err := t.apply(obj, path[i+1:], append(append(Path{}, visitedPath...), NewPathPartFromIndex(idx)))
 					if err != nil {
 						return err
 					}
@@ -68,7 +73,7 @@ func (t ObjectRefSetMod) apply(obj interface{}, path Path) error {
 				}
 
 				if *part.ArrayIndex.Index < len(typedObj) {
-					return t.apply(typedObj[*part.ArrayIndex.Index], path[i+1:])
+					return t.apply(typedObj[*part.ArrayIndex.Index], path[i+1:], append(visitedPath, part))
 				}
 
 				return nil // index not found, nothing to append to
@@ -90,5 +95,8 @@ func (t ObjectRefSetMod) apply(obj interface{}, path Path) error {
 		return fmt.Errorf("Unexpected non-map found: %T", obj)
 	}
 
+	if t.PathReplacementFunc != nil {
+		return t.PathReplacementFunc(typedObj, visitedPath)
+	}
 	return t.ReplacementFunc(typedObj)
 }
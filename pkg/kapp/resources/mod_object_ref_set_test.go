@@ -0,0 +1,84 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModObjectRefSetPathReplacement(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+spec:
+  containers:
+  - name: c1
+    env:
+    - name: key1
+  - name: c2
+    env:
+    - name: key2
+    - name: key3
+`))
+
+	var visitedPaths []string
+
+	mod := ctlres.ObjectRefSetMod{
+		ResourceMatcher: ctlres.AllMatcher{},
+		Path: ctlres.Path{
+			ctlres.NewPathPartFromString("spec"),
+			ctlres.NewPathPartFromString("containers"),
+			ctlres.NewPathPartFromIndexAll(),
+			ctlres.NewPathPartFromString("env"),
+			ctlres.NewPathPartFromIndexAll(),
+		},
+		ReplacementFunc: func(map[string]interface{}) error {
+			require.FailNow(t, "Expected replacement func to not be called")
+			return nil
+		},
+		PathReplacementFunc: func(_ map[string]interface{}, path ctlres.Path) error {
+			visitedPaths = append(visitedPaths, path.AsString())
+			return nil
+		},
+	}
+
+	err := mod.Apply(res)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"spec,containers,0,env,0",
+		"spec,containers,1,env,0",
+		"spec,containers,1,env,1",
+	}, visitedPaths)
+}
+
+func TestModObjectRefSetPathReplacementWithIndex(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+spec:
+  containers:
+  - name: c1
+  - name: c2
+`))
+
+	var visitedPaths []string
+
+	mod := ctlres.ObjectRefSetMod{
+		ResourceMatcher: ctlres.AllMatcher{},
+		Path: ctlres.Path{
+			ctlres.NewPathPartFromString("spec"),
+			ctlres.NewPathPartFromString("containers"),
+			ctlres.NewPathPartFromIndex(1),
+		},
+		PathReplacementFunc: func(_ map[string]interface{}, path ctlres.Path) error {
+			visitedPaths = append(visitedPaths, path.AsString())
+			return nil
+		},
+	}
+
+	err := mod.Apply(res)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"spec,containers,1"}, visitedPaths)
+}
@@ -14,19 +14,21 @@ import (
 )
 
 type PodWatcher struct {
+	ctx        context.Context
 	podsClient typedcorev1.PodInterface
 	listOpts   metav1.ListOptions
 }
 
 func NewPodWatcher(
+	ctx context.Context,
 	podsClient typedcorev1.PodInterface,
 	listOpts metav1.ListOptions,
 ) PodWatcher {
-	return PodWatcher{podsClient, listOpts}
+	return PodWatcher{ctx, podsClient, listOpts}
 }
 
 func (w PodWatcher) Watch(podsToWatchCh chan corev1.Pod, cancelCh chan struct{}) error {
-	podsList, err := w.podsClient.List(context.TODO(), w.listOpts)
+	podsList, err := w.podsClient.List(w.ctx, w.listOpts)
 	if err != nil {
 		return err
 	}
@@ -39,6 +41,8 @@ func (w PodWatcher) Watch(podsToWatchCh chan corev1.Pod, cancelCh chan struct{})
 	select {
 	case <-cancelCh:
 		return nil
+	case <-w.ctx.Done():
+		return nil
 	default:
 	}
 
@@ -54,7 +58,7 @@ func (w PodWatcher) Watch(podsToWatchCh chan corev1.Pod, cancelCh chan struct{})
 }
 
 func (w PodWatcher) watch(podsToWatchCh chan corev1.Pod, cancelCh chan struct{}) (bool, error) {
-	watcher, err := w.podsClient.Watch(context.TODO(), w.listOpts)
+	watcher, err := w.podsClient.Watch(w.ctx, w.listOpts)
 	if err != nil {
 		return false, fmt.Errorf("Creating Pod watcher: %w", err)
 	}
@@ -81,6 +85,9 @@ func (w PodWatcher) watch(podsToWatchCh chan corev1.Pod, cancelCh chan struct{})
 
 		case <-cancelCh:
 			return false, nil
+
+		case <-w.ctx.Done():
+			return false, nil
 		}
 	}
 }
@@ -42,6 +42,7 @@ type Resource interface {
 	CreatedAt() time.Time
 	IsProvisioned() bool
 	IsDeleting() bool
+	DeletingAt() time.Time
 	UID() string
 
 	Equal(res Resource) bool
@@ -209,6 +210,13 @@ func (r *ResourceImpl) IsProvisioned() bool {
 
 func (r *ResourceImpl) IsDeleting() bool { return r.un.GetDeletionTimestamp() != nil }
 
+func (r *ResourceImpl) DeletingAt() time.Time {
+	if ts := r.un.GetDeletionTimestamp(); ts != nil {
+		return ts.Time
+	}
+	return time.Time{}
+}
+
 func (r *ResourceImpl) MarkTransient(transient bool) { r.transient = transient }
 func (r *ResourceImpl) Transient() bool              { return r.transient }
 
@@ -212,6 +212,10 @@ func (p ResourceType) Listable() bool {
 	return p.containsStr(p.APIResource.Verbs, "list")
 }
 
+func (p ResourceType) Watchable() bool {
+	return p.containsStr(p.APIResource.Verbs, "watch")
+}
+
 func (p ResourceType) Deletable() bool {
 	return p.containsStr(p.APIResource.Verbs, "delete")
 }
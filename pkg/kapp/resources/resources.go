@@ -18,6 +18,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 )
@@ -43,16 +44,39 @@ type Resources interface {
 	Delete(Resource) error
 	Exists(Resource, ExistsOpts) (Resource, bool, error)
 	Get(Resource) (Resource, error)
-	Patch(Resource, types.PatchType, []byte) (Resource, error)
-	Update(Resource) (Resource, error)
-	Create(resource Resource) (Resource, error)
+	Patch(Resource, types.PatchType, []byte, PatchOpts) (Resource, error)
+	Update(Resource, UpdateOpts) (Resource, error)
+	Create(Resource, CreateOpts) (Resource, error)
+	Watch(ResourceType, WatchOpts) (watch.Interface, error)
+}
+
+// ResourcesWithContext is implemented by Resources
+// that can bind API requests to a context
+type ResourcesWithContext interface {
+	WithContext(context.Context) Resources
 }
 
 type ExistsOpts struct {
 	SameUID bool
 }
 
+type CreateOpts struct {
+	DryRun bool
+}
+
+type UpdateOpts struct {
+	DryRun bool
+}
+
+type PatchOpts struct {
+	// FieldManager is required for apply patches
+	FieldManager string
+	Force        bool
+	DryRun       bool
+}
+
 type ResourcesImpl struct {
+	ctx                context.Context
 	resourceTypes      ResourceTypes
 	coreClient         kubernetes.Interface
 	dynamicClient      dynamic.Interface
@@ -75,6 +99,7 @@ func NewResourcesImpl(resourceTypes ResourceTypes, coreClient kubernetes.Interfa
 	opts ResourcesImplOpts, logger logger.Logger) *ResourcesImpl {
 
 	return &ResourcesImpl{
+		ctx:                context.Background(),
 		resourceTypes:      resourceTypes,
 		coreClient:         coreClient,
 		dynamicClient:      dynamicClient,
@@ -84,6 +109,25 @@ func NewResourcesImpl(resourceTypes ResourceTypes, coreClient kubernetes.Interfa
 	}
 }
 
+var _ ResourcesWithContext = &ResourcesImpl{}
+
+// WithContext returns a copy that makes API requests with given context.
+// Resources methods do not take a context since most of callers are not
+// cancellable, hence it's only bound for applying changes so that in-flight
+// requests are aborted on cancellation (requests made via original
+// instance, e.g. to clean up, are not affected).
+func (c *ResourcesImpl) WithContext(ctx context.Context) Resources {
+	return &ResourcesImpl{
+		ctx:                ctx,
+		resourceTypes:      c.resourceTypes,
+		coreClient:         c.coreClient,
+		dynamicClient:      c.dynamicClient,
+		mutedDynamicClient: c.mutedDynamicClient,
+		opts:               c.opts,
+		logger:             c.logger,
+	}
+}
+
 type unstructItems struct {
 	ResType ResourceType
 	Items   []unstructured.Unstructured
@@ -122,9 +166,9 @@ func (c *ResourcesImpl) All(resTypes []ResourceType, opts AllOpts) ([]Resource,
 			if !c.opts.ScopeToFallbackAllowedNamespaces || !resType.Namespaced() {
 				err = util.Retry2(time.Second, 5*time.Second, c.isServerRescaleErr, func() error {
 					if resType.Namespaced() {
-						list, err = client.Namespace("").List(context.TODO(), *opts.ListOpts)
+						list, err = client.Namespace("").List(c.ctx, *opts.ListOpts)
 					} else {
-						list, err = client.List(context.TODO(), *opts.ListOpts)
+						list, err = client.List(c.ctx, *opts.ListOpts)
 					}
 					return err
 				})
@@ -186,6 +230,19 @@ func (c *ResourcesImpl) All(resTypes []ResourceType, opts AllOpts) ([]Resource,
 	return resources, nil
 }
 
+// Watch starts watching resources of given type. Watch is stopped
+// either by the caller or once context is cancelled.
+func (c *ResourcesImpl) Watch(resType ResourceType, opts WatchOpts) (watch.Interface, error) {
+	defer c.logger.DebugFunc("Watch").Finish()
+
+	client := c.mutedDynamicClient.Resource(resType.GroupVersionResource)
+
+	if resType.Namespaced() {
+		return client.Namespace(opts.Namespace).Watch(c.ctx, opts.ListOpts)
+	}
+	return client.Watch(c.ctx, opts.ListOpts)
+}
+
 func (c *ResourcesImpl) allForNamespaces(client dynamic.NamespaceableResourceInterface, listOpts *metav1.ListOptions) (*unstructured.UnstructuredList, error) {
 	defer c.logger.DebugFunc("allForNamespaces").Finish()
 
@@ -208,7 +265,7 @@ func (c *ResourcesImpl) allForNamespaces(client dynamic.NamespaceableResourceInt
 			var err error
 
 			err = util.Retry2(time.Second, 5*time.Second, c.isServerRescaleErr, func() error {
-				resList, err = client.Namespace(ns).List(context.TODO(), *listOpts)
+				resList, err = client.Namespace(ns).List(c.ctx, *listOpts)
 				return err
 			})
 			if err != nil {
@@ -241,7 +298,7 @@ func (c *ResourcesImpl) allForNamespaces(client dynamic.NamespaceableResourceInt
 	return list, nil
 }
 
-func (c *ResourcesImpl) Create(resource Resource) (Resource, error) {
+func (c *ResourcesImpl) Create(resource Resource, createOpts CreateOpts) (Resource, error) {
 	if resourcesDebug {
 		t1 := time.Now().UTC()
 		defer func() { c.logger.Debug("create %s", time.Now().UTC().Sub(t1)) }()
@@ -255,10 +312,15 @@ func (c *ResourcesImpl) Create(resource Resource) (Resource, error) {
 		return nil, err
 	}
 
+	opts := metav1.CreateOptions{}
+	if createOpts.DryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+
 	var createdUn *unstructured.Unstructured
 
 	err = util.Retry2(time.Second, 5*time.Second, c.isGeneralRetryableErr, func() error {
-		createdUn, err = resClient.Create(context.TODO(), resource.unstructuredPtr(), metav1.CreateOptions{})
+		createdUn, err = resClient.Create(c.ctx, resource.unstructuredPtr(), opts)
 		return err
 	})
 	if err != nil {
@@ -268,7 +330,7 @@ func (c *ResourcesImpl) Create(resource Resource) (Resource, error) {
 	return NewResourceUnstructured(*createdUn, resType), nil
 }
 
-func (c *ResourcesImpl) Update(resource Resource) (Resource, error) {
+func (c *ResourcesImpl) Update(resource Resource, updateOpts UpdateOpts) (Resource, error) {
 	if resourcesDebug {
 		t1 := time.Now().UTC()
 		defer func() { c.logger.Debug("update %s", time.Now().UTC().Sub(t1)) }()
@@ -282,10 +344,15 @@ func (c *ResourcesImpl) Update(resource Resource) (Resource, error) {
 		return nil, err
 	}
 
+	opts := metav1.UpdateOptions{}
+	if updateOpts.DryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+
 	var updatedUn *unstructured.Unstructured
 
 	err = util.Retry2(time.Second, 5*time.Second, c.isGeneralRetryableErr, func() error {
-		updatedUn, err = resClient.Update(context.TODO(), resource.unstructuredPtr(), metav1.UpdateOptions{})
+		updatedUn, err = resClient.Update(c.ctx, resource.unstructuredPtr(), opts)
 		return err
 	})
 	if err != nil {
@@ -295,7 +362,7 @@ func (c *ResourcesImpl) Update(resource Resource) (Resource, error) {
 	return NewResourceUnstructured(*updatedUn, resType), nil
 }
 
-func (c *ResourcesImpl) Patch(resource Resource, patchType types.PatchType, data []byte) (Resource, error) {
+func (c *ResourcesImpl) Patch(resource Resource, patchType types.PatchType, data []byte, patchOpts PatchOpts) (Resource, error) {
 	if resourcesDebug {
 		t1 := time.Now().UTC()
 		defer func() { c.logger.Debug("patch %s", time.Now().UTC().Sub(t1)) }()
@@ -306,10 +373,18 @@ func (c *ResourcesImpl) Patch(resource Resource, patchType types.PatchType, data
 		return nil, err
 	}
 
+	opts := metav1.PatchOptions{FieldManager: patchOpts.FieldManager}
+	if patchType == types.ApplyPatchType {
+		opts.Force = &patchOpts.Force
+	}
+	if patchOpts.DryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+
 	var patchedUn *unstructured.Unstructured
 
 	err = util.Retry2(time.Second, 5*time.Second, c.isGeneralRetryableErr, func() error {
-		patchedUn, err = resClient.Patch(context.TODO(), resource.Name(), patchType, data, metav1.PatchOptions{})
+		patchedUn, err = resClient.Patch(c.ctx, resource.Name(), patchType, data, opts)
 		return err
 	})
 	if err != nil {
@@ -347,7 +422,7 @@ func (c *ResourcesImpl) Delete(resource Resource) error {
 			delOpts.Preconditions = &metav1.Preconditions{UID: &resUID}
 		}
 
-		err = resClient.Delete(context.TODO(), resource.Name(), delOpts)
+		err = resClient.Delete(c.ctx, resource.Name(), delOpts)
 		if err != nil {
 			if errors.IsNotFound(err) {
 				c.logger.Info("TODO resource '%s' is already gone", resource.Description())
@@ -380,7 +455,7 @@ func (c *ResourcesImpl) Get(resource Resource) (Resource, error) {
 
 	err = util.Retry2(time.Second, 5*time.Second, c.isServerRescaleErr, func() error {
 		var err error
-		item, err = resClient.Get(context.TODO(), resource.Name(), metav1.GetOptions{})
+		item, err = resClient.Get(c.ctx, resource.Name(), metav1.GetOptions{})
 		return err
 	})
 	if err != nil {
@@ -410,7 +485,7 @@ func (c *ResourcesImpl) Exists(resource Resource, existsOpts ExistsOpts) (Resour
 	var resObj Resource
 
 	err = util.Retry(time.Second, time.Minute, func() (bool, error) {
-		fetchedRes, err := resClient.Get(context.TODO(), resource.Name(), metav1.GetOptions{})
+		fetchedRes, err := resClient.Get(c.ctx, resource.Name(), metav1.GetOptions{})
 		if err != nil {
 			if errors.IsNotFound(err) {
 				found = false
@@ -544,7 +619,7 @@ func (c *ResourcesImpl) assumedAllowedNamespaces() ([]string, error) {
 		return c.opts.FallbackAllowedNamespaces, nil
 	}
 
-	nsList, err := c.coreClient.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	nsList, err := c.coreClient.CoreV1().Namespaces().List(c.ctx, metav1.ListOptions{})
 	if err != nil {
 		if errors.IsForbidden(err) {
 			if len(c.opts.FallbackAllowedNamespaces) > 0 {
@@ -618,6 +693,13 @@ type AllOpts struct {
 	ResourceNamespaces []string
 }
 
+type WatchOpts struct {
+	// Namespace is ignored for cluster scoped resources;
+	// empty value means all namespaces
+	Namespace string
+	ListOpts  metav1.ListOptions
+}
+
 type resourceStatusErr struct {
 	err    resourcePlainErr
 	status metav1.Status
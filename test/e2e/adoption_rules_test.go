@@ -0,0 +1,85 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdoptionRules(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	const existingAppName1 = "test-adoption-existing-1"
+	const existingAppName2 = "test-adoption-existing-2"
+	const newAppName = "test-adoption-new"
+
+	cm1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: adopted-by-matcher
+`
+
+	cm2 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: adopted-by-app
+`
+
+	cm3 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: not-adopted
+`
+
+	config := `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+adoptionRules:
+- resourceMatchers:
+  - kindNamespaceNameMatcher: {kind: ConfigMap, namespace: ` + env.Namespace + `, name: adopted-by-matcher}
+- apps: [` + existingAppName2 + `]
+`
+
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", existingAppName1})
+		kapp.Run([]string{"delete", "-a", existingAppName2})
+		kapp.Run([]string{"delete", "-a", newAppName})
+	}
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy existing apps", func() {
+		kapp.RunWithOpts([]string{"deploy", "-a", existingAppName1, "-f", "-"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(cm1 + cm3)})
+		kapp.RunWithOpts([]string{"deploy", "-a", existingAppName2, "-f", "-"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(cm2)})
+	})
+
+	logger.Section("deploy fails for resources not matched by adoption rules", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-a", newAppName, "-f", "-"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(config + cm1 + cm2 + cm3)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "configmap/not-adopted")
+		require.NotContains(t, err.Error(), "configmap/adopted-by-matcher")
+		require.NotContains(t, err.Error(), "configmap/adopted-by-app")
+	})
+
+	logger.Section("deploy adopts resources matched by adoption rules", func() {
+		kapp.RunWithOpts([]string{"deploy", "-a", newAppName, "-f", "-"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(config + cm1 + cm2)})
+	})
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppChangeRollback(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: rollback-config
+data:
+  key: value1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: rollback-config-extra
+data:
+  key: value
+`
+
+	yaml2 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: rollback-config
+data:
+  key: value2
+`
+
+	name := "test-app-change-rollback"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy two versions of app", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		NewMissingClusterResource(t, "configmap", "rollback-config-extra", env.Namespace, kubectl)
+	})
+
+	firstChangeName := ""
+
+	logger.Section("rollback to first app change", func() {
+		out, _ := kapp.RunWithOpts([]string{"app-change", "ls", "-a", name, "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Equal(t, 2, len(resp.Tables[0].Rows), "Expected to have 2 app-changes")
+
+		firstChangeName = resp.Tables[0].Rows[1]["name"]
+
+		NewPresentClusterResource("secret", firstChangeName, env.Namespace, kubectl)
+
+		kapp.RunWithOpts([]string{"app-change", "rollback", "-a", name, "--change", firstChangeName}, RunOpts{})
+
+		cm := NewPresentClusterResource("configmap", "rollback-config", env.Namespace, kubectl)
+		require.Equal(t, "value1", cm.RawPath(ctlres.NewPathFromStrings([]string{"data", "key"})))
+
+		NewPresentClusterResource("configmap", "rollback-config-extra", env.Namespace, kubectl)
+	})
+
+	logger.Section("rollback to missing app change", func() {
+		_, err := kapp.RunWithOpts([]string{"app-change", "rollback", "-a", name, "--change", "missing-change"},
+			RunOpts{AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected to find app change 'missing-change'")
+	})
+
+	logger.Section("garbage collecting app changes deletes recorded resources", func() {
+		kapp.RunWithOpts([]string{"app-change", "gc", "-a", name, "--max", "1"}, RunOpts{})
+
+		NewMissingClusterResource(t, "secret", firstChangeName, env.Namespace, kubectl)
+	})
+}
+
+func TestAppChangeRollbackWithRecordedFlags(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml := func(val string) string {
+		return `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: rollback-config
+  namespace: rollback-src-ns
+data:
+  key: ` + val + `
+`
+	}
+
+	name := "test-app-change-rollback-recorded-flags"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy two versions of app with mapped namespace", func() {
+		for _, val := range []string{"value1", "value2"} {
+			kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--map-ns", "rollback-src-ns=" + env.Namespace},
+				RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml(val))})
+		}
+	})
+
+	logger.Section("rollback uses flags recorded with app change", func() {
+		out, _ := kapp.RunWithOpts([]string{"app-change", "ls", "-a", name, "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Equal(t, 2, len(resp.Tables[0].Rows), "Expected to have 2 app-changes")
+
+		kapp.RunWithOpts([]string{"app-change", "rollback", "-a", name, "--change", resp.Tables[0].Rows[1]["name"]}, RunOpts{})
+
+		cm := NewPresentClusterResource("configmap", "rollback-config", env.Namespace, kubectl)
+		require.Equal(t, "value1", cm.RawPath(ctlres.NewPathFromStrings([]string{"data", "key"})))
+	})
+}
@@ -6,6 +6,7 @@ package e2e
 import (
 	"os"
 	"path"
+	"strings"
 	"testing"
 
 	ctlres "carvel.dev/kapp/pkg/kapp/resources"
@@ -166,3 +167,233 @@ data:
 		require.Equalf(t, expectedAppsList, replaceLastChangeAge(resp.Tables[0].Rows), "Expected to match")
 	})
 }
+
+func TestAppGroupDeployWithDependencies(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	infraApp := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: infra-config
+`
+
+	appConfig := `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: AppGroupApp
+dependsOn:
+- z-infra
+`
+
+	app := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+`
+
+	appGroupDir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+
+	files := map[string]string{
+		"z-infra/config.yml":   infraApp,
+		"a-app/config.yml":     app,
+		"a-app/kapp-app.yml":   appConfig,
+		"b-other/config.yml":   `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "other-config"}}`,
+		"b-other/kapp-app.yml": `{"apiVersion": "kapp.k14s.io/v1alpha1", "kind": "AppGroupApp", "dependsOn": ["a-app"]}`,
+	}
+
+	for filePath, content := range files {
+		require.NoError(t, os.MkdirAll(path.Join(appGroupDir, path.Dir(filePath)), os.ModePerm))
+		require.NoError(t, os.WriteFile(path.Join(appGroupDir, filePath), []byte(content), os.ModePerm))
+	}
+
+	name := "test-app-group-deps"
+	cleanUp := func() {
+		kapp.RunWithOpts([]string{"app-group", "delete", "-g", name}, RunOpts{})
+	}
+
+	cleanUp()
+	defer cleanUp()
+	defer os.RemoveAll(appGroupDir)
+
+	logger.Section("deploy apps after their dependencies", func() {
+		out, _ := kapp.RunWithOpts([]string{"app-group", "deploy", "-g", name, "--directory", appGroupDir}, RunOpts{IntoNs: true})
+
+		infraIdx := strings.Index(out, "--- deploying app '"+name+"-z-infra'")
+		appIdx := strings.Index(out, "--- deploying app '"+name+"-a-app'")
+		otherIdx := strings.Index(out, "--- deploying app '"+name+"-b-other'")
+
+		require.True(t, infraIdx >= 0 && appIdx >= 0 && otherIdx >= 0, "Expected all apps to be deployed")
+		require.Less(t, infraIdx, appIdx)
+		require.Less(t, appIdx, otherIdx)
+
+		NewPresentClusterResource("configmap", "infra-config", env.Namespace, kubectl)
+		NewPresentClusterResource("configmap", "app-config", env.Namespace, kubectl)
+		NewPresentClusterResource("configmap", "other-config", env.Namespace, kubectl)
+	})
+
+	logger.Section("delete apps before their dependencies", func() {
+		out, _ := kapp.RunWithOpts([]string{"app-group", "delete", "-g", name}, RunOpts{})
+
+		infraIdx := strings.Index(out, "--- deleting app '"+name+"-z-infra'")
+		appIdx := strings.Index(out, "--- deleting app '"+name+"-a-app'")
+		otherIdx := strings.Index(out, "--- deleting app '"+name+"-b-other'")
+
+		require.True(t, infraIdx >= 0 && appIdx >= 0 && otherIdx >= 0, "Expected all apps to be deleted")
+		require.Less(t, otherIdx, appIdx)
+		require.Less(t, appIdx, infraIdx)
+
+		NewMissingClusterResource(t, "configmap", "infra-config", env.Namespace, kubectl)
+	})
+
+	logger.Section("deploy fails with cyclic dependencies", func() {
+		err := os.WriteFile(path.Join(appGroupDir, "z-infra", "kapp-app.yml"),
+			[]byte(`{"apiVersion": "kapp.k14s.io/v1alpha1", "kind": "AppGroupApp", "dependsOn": ["b-other"]}`), os.ModePerm)
+		require.NoError(t, err)
+
+		_, err = kapp.RunWithOpts([]string{"app-group", "deploy", "-g", name, "--directory", appGroupDir},
+			RunOpts{IntoNs: true, AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "found cycle between apps")
+	})
+}
+
+func TestAppGroupDeployConcurrently(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	appGroupDir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+
+	files := map[string]string{
+		"app1/config.yml":    `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "concurrent-config1"}}`,
+		"app2/config.yml":    `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "concurrent-config2"}}`,
+		"app3/config.yml":    `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "concurrent-config3"}}`,
+		"app3/kapp-app.yml":  `{"apiVersion": "kapp.k14s.io/v1alpha1", "kind": "AppGroupApp", "dependsOn": ["app1", "app2"]}`,
+		"failing/config.yml": `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "concurrent-failing"}, "data": "invalid"}`,
+	}
+
+	for filePath, content := range files {
+		require.NoError(t, os.MkdirAll(path.Join(appGroupDir, path.Dir(filePath)), os.ModePerm))
+		require.NoError(t, os.WriteFile(path.Join(appGroupDir, filePath), []byte(content), os.ModePerm))
+	}
+
+	name := "test-app-group-concurrent"
+	cleanUp := func() {
+		kapp.RunWithOpts([]string{"app-group", "delete", "-g", name}, RunOpts{})
+	}
+
+	cleanUp()
+	defer cleanUp()
+	defer os.RemoveAll(appGroupDir)
+
+	logger.Section("deploy independent apps concurrently and aggregate failures", func() {
+		out, err := kapp.RunWithOpts([]string{"app-group", "deploy", "-g", name,
+			"--directory", appGroupDir, "--app-concurrency", "3"}, RunOpts{IntoNs: true, AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Deploying app '"+name+"-failing'")
+
+		require.Contains(t, out, name+"-app1 | --- deploying app '"+name+"-app1'")
+		require.Contains(t, out, name+"-app2 | --- deploying app '"+name+"-app2'")
+		require.Contains(t, out, name+"-app3 | --- deploying app '"+name+"-app3'")
+
+		NewPresentClusterResource("configmap", "concurrent-config1", env.Namespace, kubectl)
+		NewPresentClusterResource("configmap", "concurrent-config2", env.Namespace, kubectl)
+		NewPresentClusterResource("configmap", "concurrent-config3", env.Namespace, kubectl)
+	})
+
+	logger.Section("skip apps that depend on failed apps", func() {
+		err := os.WriteFile(path.Join(appGroupDir, "app3", "kapp-app.yml"),
+			[]byte(`{"apiVersion": "kapp.k14s.io/v1alpha1", "kind": "AppGroupApp", "dependsOn": ["failing"]}`), os.ModePerm)
+		require.NoError(t, err)
+
+		_, err = kapp.RunWithOpts([]string{"app-group", "deploy", "-g", name,
+			"--directory", appGroupDir, "--app-concurrency", "3"}, RunOpts{IntoNs: true, AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Skipped deploying app '"+name+"-app3' since its dependency '"+name+"-failing' failed")
+	})
+}
+
+func TestAppGroupDeployFromFile(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	appGroupDir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+
+	appGroupConfig := `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: AppGroup
+apps:
+- name: web
+  path: web/
+  intoNamespace: ` + env.Namespace + `
+  applyTimeout: 5m
+  dependsOn: [infra]
+- name: infra
+  path: infra.yml
+  labels: ["test-app-group-file=infra"]
+  waitTimeout: 2m
+`
+
+	files := map[string]string{
+		"group.yml": appGroupConfig,
+		"infra.yml": `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "file-infra-config"}}`,
+		// Resource namespace is replaced via intoNamespace setting
+		"web/config.yml": `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "file-web-config", "namespace": "non-existent"}}`,
+	}
+
+	for filePath, content := range files {
+		require.NoError(t, os.MkdirAll(path.Join(appGroupDir, path.Dir(filePath)), os.ModePerm))
+		require.NoError(t, os.WriteFile(path.Join(appGroupDir, filePath), []byte(content), os.ModePerm))
+	}
+
+	name := "test-app-group-file"
+	cleanUp := func() {
+		kapp.RunWithOpts([]string{"app-group", "delete", "-g", name}, RunOpts{})
+	}
+
+	cleanUp()
+	defer cleanUp()
+	defer os.RemoveAll(appGroupDir)
+
+	logger.Section("deploy apps listed in file with their settings", func() {
+		out, _ := kapp.RunWithOpts([]string{"app-group", "deploy", "-g", name,
+			"-f", path.Join(appGroupDir, "group.yml")}, RunOpts{IntoNs: true})
+
+		infraIdx := strings.Index(out, "--- deploying app '"+name+"-infra'")
+		webIdx := strings.Index(out, "--- deploying app '"+name+"-web'")
+
+		require.True(t, infraIdx >= 0 && webIdx >= 0, "Expected all apps to be deployed")
+		require.Less(t, infraIdx, webIdx)
+
+		NewPresentClusterResource("configmap", "file-infra-config", env.Namespace, kubectl)
+		NewPresentClusterResource("configmap", "file-web-config", env.Namespace, kubectl)
+
+		appConfigMaps := kubectl.Run([]string{"get", "configmaps", "-l", "test-app-group-file=infra", "-o", "name"})
+		require.Contains(t, appConfigMaps, name+"-infra")
+		require.NotContains(t, appConfigMaps, name+"-web")
+	})
+
+	logger.Section("deploy fails when both directory and file are specified", func() {
+		_, err := kapp.RunWithOpts([]string{"app-group", "deploy", "-g", name, "-d", appGroupDir,
+			"-f", path.Join(appGroupDir, "group.yml")}, RunOpts{IntoNs: true, AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected only one of --directory (-d) or --file (-f) to be specified")
+	})
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppLock(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: lock-config
+data:
+  key: value1
+`
+
+	yaml2 := strings.Replace(yaml1, "value1", "value2", 1)
+	yaml3 := strings.Replace(yaml1, "value1", "value3", 1)
+
+	name := "test-app-lock"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name, "--force-unlock"})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	lockAnn := func(holder string, renewedAt time.Time) string {
+		return fmt.Sprintf(`kapp.k14s.io/lock={"holder":"%s","acquiredAt":"%s","renewedAt":"%s"}`,
+			holder, renewedAt.Format(time.RFC3339), renewedAt.Format(time.RFC3339))
+	}
+
+	logger.Section("deploy releases lock once done", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		out, _ := kubectl.RunWithOpts([]string{"get", "configmap", name, "-o", "jsonpath={.metadata.annotations}"}, RunOpts{})
+		require.NotContains(t, out, "kapp.k14s.io/lock")
+	})
+
+	logger.Section("deploy fails if app is locked by another run", func() {
+		kubectl.Run([]string{"annotate", "configmap", name, "--overwrite", lockAnn("other-pipeline", time.Now().UTC())})
+
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml2)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is locked by 'other-pipeline'")
+
+		_, err = kapp.RunWithOpts([]string{"delete", "-a", name}, RunOpts{AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is locked by 'other-pipeline'")
+	})
+
+	logger.Section("diff run does not require lock", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+	})
+
+	logger.Section("deploy takes over lock with --force-unlock", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--force-unlock"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+	})
+
+	logger.Section("deploy takes over stale lock", func() {
+		kubectl.Run([]string{"annotate", "configmap", name, "--overwrite", lockAnn("crashed-pipeline", time.Now().UTC().Add(-time.Hour))})
+
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml3)})
+	})
+}
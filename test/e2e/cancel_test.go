@@ -0,0 +1,65 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancelDeploy(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: cancel-job
+spec:
+  template:
+    spec:
+      containers:
+      - name: sleep
+        image: busybox
+        command: ["sleep", "3600"]
+      restartPolicy: Never
+`
+
+	name := "test-cancel-deploy"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("interrupt deploy while waiting", func() {
+		cancelCh := make(chan struct{})
+		time.AfterFunc(15*time.Second, func() { close(cancelCh) })
+
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, AllowError: true, CancelCh: cancelCh, StdinReader: strings.NewReader(yaml1)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Cancelled applying changes")
+		require.Contains(t, err.Error(), "exit code: '130'")
+	})
+
+	logger.Section("app change is marked as cancelled", func() {
+		out, _ := kapp.RunWithOpts([]string{"app-change", "ls", "-a", name, "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		require.Equal(t, 1, len(resp.Tables[0].Rows), "Expected to have 1 app-change")
+		require.Equal(t, "false", resp.Tables[0].Rows[0]["successful"])
+		require.Equal(t, "cancelled", resp.Tables[0].Rows[0]["failure_reason"])
+	})
+}
@@ -4,6 +4,7 @@
 package e2e
 
 import (
+	"fmt"
 	"regexp"
 	"testing"
 
@@ -50,3 +51,18 @@ func replaceAnnsLabels(in string) string {
 	replaceAnns := regexp.MustCompile("kapp\\.k14s\\.io\\/(app|association): .+")
 	return replaceAnns.ReplaceAllString(in, "-replaced-")
 }
+
+// replaceFingerprints numbers masked value fingerprints in order of appearance
+// since fingerprints are salted differently for each kapp invocation
+func replaceFingerprints(in string) string {
+	nums := map[string]int{}
+	replaceFingerprints := regexp.MustCompile("fingerprint: [0-9a-f]{8}")
+	return replaceFingerprints.ReplaceAllStringFunc(in, func(fingerprint string) string {
+		num, found := nums[fingerprint]
+		if !found {
+			num = len(nums) + 1
+			nums[fingerprint] = num
+		}
+		return fmt.Sprintf("fingerprint: #%d", num)
+	})
+}
@@ -0,0 +1,54 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteForceFinalize(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: stuck-config
+  annotations:
+    kapp.k14s.io/delete-strategy: force-finalize
+  finalizers:
+  - kapp.k14s.io/test-never-removed
+data:
+  key: value
+`
+
+	name := "test-delete-force-finalize"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name, "--wait-force-finalize-grace-period", "0s"})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy resource with finalizer", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+		NewPresentClusterResource("configmap", "stuck-config", env.Namespace, kubectl)
+	})
+
+	logger.Section("delete removes finalizers after grace period", func() {
+		out, _ := kapp.RunWithOpts([]string{"delete", "-a", name, "--wait-force-finalize-grace-period", "3s"}, RunOpts{})
+
+		require.Contains(t, out, "WARNING: Forcefully removed finalizers (kapp.k14s.io/test-never-removed) "+
+			"from configmap/stuck-config (v1) namespace: "+env.Namespace)
+		NewMissingClusterResource(t, "configmap", "stuck-config", env.Namespace, kubectl)
+	})
+}
@@ -0,0 +1,155 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeletionProtection(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	config := `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+deletionProtectionRules:
+- resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Secret}
+`
+
+	protectedByAnn := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: protected-by-ann
+  annotations:
+    kapp.k14s.io/prevent-delete: ""
+`
+
+	protectedByRule := `
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: protected-by-rule
+`
+
+	unprotected := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unprotected
+`
+
+	name := "test-deletion-protection"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name,
+			"--dangerous-allow-deleting-protected-resource", "ConfigMap/" + env.Namespace + "/protected-by-ann",
+			"--dangerous-allow-deleting-protected-resource", "Secret/" + env.Namespace + "/protected-by-rule"})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(config + protectedByAnn + protectedByRule + unprotected)})
+	})
+
+	logger.Section("deleting unprotected resource succeeds", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(config + protectedByAnn + protectedByRule)})
+	})
+
+	logger.Section("deploy fails to delete protected resources", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(config)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Refusing to delete 2 protected resource(s): "+
+			"ConfigMap/"+env.Namespace+"/protected-by-ann, Secret/"+env.Namespace+"/protected-by-rule")
+	})
+
+	logger.Section("diff run does not check protection", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(config)})
+	})
+
+	logger.Section("delete fails to delete protected resources unless allowed", func() {
+		_, err := kapp.RunWithOpts([]string{"delete", "-a", name,
+			"--dangerous-allow-deleting-protected-resource", "ConfigMap/" + env.Namespace + "/protected-by-ann"},
+			RunOpts{AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Refusing to delete 1 protected resource(s): Secret/"+env.Namespace+"/protected-by-rule")
+	})
+
+	logger.Section("deploy deletes explicitly allowed resources", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name,
+			"--dangerous-allow-deleting-protected-resource", "ConfigMap/" + env.Namespace + "/protected-by-ann"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(config + protectedByRule)})
+	})
+}
+
+func TestDeletionProtectionAfterPartialDelete(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml := `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+deletionProtectionRules:
+- resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: rbac.authorization.k8s.io/v1, kind: Role}
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: protected-by-rule
+rules: []
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unprotected
+`
+
+	protectedRef := "Role.rbac.authorization.k8s.io/" + env.Namespace + "/protected-by-rule"
+
+	name := "test-deletion-protection-after-partial-delete"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name, "--dangerous-allow-deleting-protected-resource", protectedRef})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+	})
+
+	logger.Section("partially delete app", func() {
+		kapp.RunWithOpts([]string{"delete", "-a", name, "--filter-kind", "ConfigMap"}, RunOpts{})
+	})
+
+	logger.Section("delete still uses deletion protection rules recorded during last deploy", func() {
+		_, err := kapp.RunWithOpts([]string{"delete", "-a", name,
+			"--dangerous-allow-deleting-protected-resource", "Role/" + env.Namespace + "/protected-by-rule"},
+			RunOpts{AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Refusing to delete 1 protected resource(s): "+protectedRef)
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployPlan(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: plan-config
+  annotations:
+    kapp.k14s.io/nonce: ""
+data:
+  key: value1
+`
+
+	yaml2 := strings.Replace(yaml1, "value1", "value2", 1)
+	yaml3 := strings.Replace(yaml1, "value1", "value3", 1)
+
+	name := "test-deploy-plan"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+
+	logger.Section("write plan without applying changes", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--plan-output", planPath},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		planBytes, err := os.ReadFile(planPath)
+		require.NoError(t, err)
+		require.Contains(t, string(planBytes), `"opsDiffMD5"`)
+
+		NewMissingClusterResource(t, "configmap", "plan-config", env.Namespace, kubectl)
+	})
+
+	logger.Section("apply plan", func() {
+		kapp.RunWithOpts([]string{"deploy", "-a", name, "--from-plan", planPath}, RunOpts{IntoNs: true})
+
+		cm := NewPresentClusterResource("configmap", "plan-config", env.Namespace, kubectl)
+		require.Equal(t, "value1", cm.RawPath(ctlres.NewPathFromStrings([]string{"data", "key"})))
+	})
+
+	logger.Section("refuse to apply plan if cluster changed", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--plan-output", planPath},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml3)})
+
+		_, err := kapp.RunWithOpts([]string{"deploy", "-a", name, "--from-plan", planPath},
+			RunOpts{IntoNs: true, AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Refusing to apply deploy plan since calculated changes differ from planned changes")
+
+		cm := NewPresentClusterResource("configmap", "plan-config", env.Namespace, kubectl)
+		require.Equal(t, "value3", cm.RawPath(ctlres.NewPathFromStrings([]string{"data", "key"})))
+	})
+}
@@ -129,8 +129,8 @@ data:
 # create: secret/mysecret (v1) namespace: kapp-test
 apiVersion: v1
 data:
-  password: <-- value not shown (#1)
-  username: <-- value not shown (#2)
+  password: <-- value not shown (fingerprint: #1)
+  username: <-- value not shown (fingerprint: #2)
 kind: Secret
 metadata:
   labels:
@@ -139,7 +139,7 @@ metadata:
 ---
 # delete: configmap/simple-cm (v1) namespace: kapp-test
 `
-		out = strings.TrimSpace(replaceTarget(replaceSpaces(replaceTs(out))))
+		out = strings.TrimSpace(replaceTarget(replaceSpaces(replaceTs(replaceFingerprints(out)))))
 		out = clearKeys(fieldsExcludedInMatch, out)
 		expectedOutput = strings.TrimSpace(replaceSpaces(expectedOutput))
 		require.Contains(t, out, expectedOutput, "output does not match")
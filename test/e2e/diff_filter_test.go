@@ -253,6 +253,7 @@ data:
 			"op_strategy":     "",
 			"reconcile_info":  "",
 			"reconcile_state": "ok",
+			"risk":            "",
 			"wait_to":         "delete",
 		}, {
 			"kind":            "Endpoints",
@@ -262,6 +263,7 @@ data:
 			"op_strategy":     "",
 			"reconcile_info":  "",
 			"reconcile_state": "ok",
+			"risk":            "",
 			"wait_to":         "delete",
 		}, {
 			"kind":            "Service",
@@ -271,6 +273,7 @@ data:
 			"op_strategy":     "",
 			"reconcile_info":  "",
 			"reconcile_state": "ok",
+			"risk":            "medium: deletes service (clients may lose connectivity)",
 			"wait_to":         "delete",
 		}}
 
@@ -0,0 +1,143 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffFormat(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  key: value1
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret1
+stringData:
+  password: secret-value1
+`
+
+	yaml2 := strings.ReplaceAll(yaml1, "value1", "value2")
+
+	name := "test-diff-format"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	type formattedChanges struct {
+		Version string
+		Changes []struct {
+			Resource struct {
+				Kind string
+				Name string
+			}
+			ApplyOp   string
+			WaitOp    string
+			OpsDiff   []map[string]interface{}
+			JSONPatch []map[string]interface{}
+			TextDiff  string
+		}
+	}
+
+	parseChanges := func(out string) formattedChanges {
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Len(t, resp.Blocks, 1)
+		require.Len(t, resp.Tables, 0, "Expected summary table to not be printed")
+
+		var changes formattedChanges
+		require.NoError(t, json.Unmarshal([]byte(resp.Blocks[0]), &changes))
+		require.Equal(t, "kapp.k14s.io/v1alpha1/Changes", changes.Version)
+		return changes
+	}
+
+	logger.Section("json format for new resources", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "--diff-format", "json", "--json"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		changes := parseChanges(out)
+		require.Len(t, changes.Changes, 2)
+
+		require.Equal(t, "ConfigMap", changes.Changes[0].Resource.Kind)
+		require.Equal(t, "create", changes.Changes[0].ApplyOp)
+		require.Equal(t, "reconcile", changes.Changes[0].WaitOp)
+		require.NotEmpty(t, changes.Changes[0].OpsDiff)
+		require.Contains(t, changes.Changes[0].TextDiff, "+  key: value1")
+	})
+
+	kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+	logger.Section("json-patch format for updated resources", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "--diff-format", "json-patch", "--json"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		changes := parseChanges(out)
+		require.Len(t, changes.Changes, 2)
+
+		cmChange := changes.Changes[0]
+		require.Equal(t, "cm1", cmChange.Resource.Name)
+		require.Equal(t, "update", cmChange.ApplyOp)
+		require.Empty(t, cmChange.OpsDiff)
+		require.Contains(t, cmChange.JSONPatch, map[string]interface{}{"op": "replace", "path": "/data/key", "value": "value2"})
+
+		secretChange := changes.Changes[1]
+		require.Equal(t, "secret1", secretChange.Resource.Name)
+		require.Equal(t, "update", secretChange.ApplyOp)
+		require.NotContains(t, out, "secret-value", "Expected secret values to be masked")
+	})
+
+	logger.Section("unified format", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "--diff-format", "unified"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		require.Contains(t, out, "--- a/configmap/cm1 (v1) namespace: kapp-test\n")
+		require.Contains(t, out, "-  key: value1\n+  key: value2\n")
+		require.NotContains(t, out, "Changes")
+	})
+
+	logger.Section("format written to file", func() {
+		outputPath := filepath.Join(t.TempDir(), "changes.json")
+
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "--diff-format", "json", "--diff-format-output", outputPath},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		require.Contains(t, out, "Wrote changes to '"+outputPath+"'")
+		require.NotContains(t, out, "kapp.k14s.io/v1alpha1/Changes")
+
+		outputBs, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+
+		var changes formattedChanges
+		require.NoError(t, json.Unmarshal(outputBs, &changes))
+		require.Equal(t, "kapp.k14s.io/v1alpha1/Changes", changes.Version)
+		require.Len(t, changes.Changes, 2)
+	})
+
+	logger.Section("invalid format", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "--diff-format", "yaml"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml2)})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected format to be one of: json, json-patch, unified")
+	})
+}
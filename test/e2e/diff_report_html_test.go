@@ -0,0 +1,86 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffReportHTML(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-report
+data:
+  key: value1
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret-report
+stringData:
+  password: secret-value1
+`
+
+	name := "test-diff-report-html"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	reportFile, err := os.CreateTemp("", "kapp-test-diff-report")
+	require.NoError(t, err)
+	reportFile.Close()
+	defer os.Remove(reportFile.Name())
+
+	readReport := func() string {
+		bs, err := os.ReadFile(reportFile.Name())
+		require.NoError(t, err)
+
+		report := string(bs)
+		require.Contains(t, report, "<script>")
+		require.NotContains(t, report, "/assets/", "Expected report to be self-contained")
+		return report
+	}
+
+	logger.Section("deploy diff report", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "--diff-report-html", reportFile.Name()},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		require.Contains(t, out, "Wrote diff report to '"+reportFile.Name()+"'")
+
+		report := readReport()
+		require.Contains(t, report, `"name":"cm-report"`)
+		require.Contains(t, report, `+   key: value1"`)
+		require.Contains(t, report, `"op":"create"`)
+		require.NotContains(t, report, "secret-value1", "Expected secret values to be masked")
+
+		NewMissingClusterResource(t, "configmap", "cm-report", env.Namespace, kubectl)
+	})
+
+	kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+	logger.Section("delete diff report", func() {
+		kapp.RunWithOpts([]string{"delete", "-a", name, "--diff-run", "--diff-report-html", reportFile.Name()}, RunOpts{})
+
+		report := readReport()
+		require.Contains(t, report, `"name":"cm-report"`)
+		require.Contains(t, report, `"op":"delete"`)
+
+		NewPresentClusterResource("configmap", "cm-report", env.Namespace, kubectl)
+	})
+}
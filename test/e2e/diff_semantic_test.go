@@ -0,0 +1,96 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSemantic(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  selector:
+    app: app
+  ports:
+  - name: http
+    port: 80
+  - name: https
+    port: 443
+`
+
+	yaml2 := `
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  selector:
+    app: app
+  ports:
+  - name: metrics
+    port: 9090
+  - name: https
+    port: 443
+  - name: http
+    port: 8080
+`
+
+	name := "test-diff-semantic"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("diff matches list items by merge key", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "-c", "--diff-semantic"},
+			RunOpts{StdinReader: strings.NewReader(yaml2)})
+
+		require.Contains(t, out, "+ spec.ports[port=9090]:")
+		require.Contains(t, out, "+ spec.ports[port=8080]:")
+		require.Contains(t, out, "- spec.ports[port=80]:")
+		require.NotContains(t, out, "spec.ports[port=443]", "Expected reordered port to not be shown as changed")
+	})
+
+	logger.Section("reordering only", func() {
+		yaml3 := `
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  selector:
+    app: app
+  ports:
+  - name: https
+    port: 443
+  - name: http
+    port: 80
+`
+
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "-c", "--diff-semantic"},
+			RunOpts{StdinReader: strings.NewReader(yaml3)})
+
+		require.Contains(t, out, "(no changes besides ordering of list items)")
+	})
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffServerSideDryRun(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: redis-primary
+spec:
+  ports:
+  - port: 6380
+  selector:
+    app: redis
+`
+
+	yaml2 := `
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: redis-primary
+spec:
+  ports:
+  - port: 6380
+    protocol: SCTP
+  selector:
+    app: redis
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: redis-invalid
+spec:
+  ports:
+  - port: 99999
+  selector:
+    app: redis
+`
+
+	name := "test-diff-server-side-dry-run"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("dry run new resource shows server defaults", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name,
+			"--diff-run", "--diff-changes", "--diff-server-side-dry-run"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		require.Contains(t, out, "targetPort: 6380", "Expected defaulted target port in diff")
+		require.Contains(t, out, "sessionAffinity: None", "Expected defaulted session affinity in diff")
+
+		NewMissingClusterResource(t, "service", "redis-primary", env.Namespace, kubectl)
+	})
+
+	logger.Section("dry run shows failures in summary", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		out, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name,
+			"--diff-run", "--diff-server-side-dry-run", "--json"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml2)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Server-side dry run failed for 1 changes")
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		dryRunByName := map[string]string{}
+		for _, row := range resp.Tables[0].Rows {
+			dryRunByName[row["name"]] = row["dry_run"]
+		}
+
+		require.Equal(t, map[string]string{"redis-primary": "ok", "redis-invalid": "fail"}, dryRunByName)
+	})
+
+	logger.Section("dry run skips resources in namespace added by the same deploy", func() {
+		nsYAML := `
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: test-diff-server-side-dry-run-ns
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: test-diff-server-side-dry-run-ns
+data:
+  key: value
+`
+
+		nsName := "test-diff-server-side-dry-run-ns-app"
+		defer kapp.Run([]string{"delete", "-a", nsName})
+
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", nsName,
+			"--diff-server-side-dry-run", "--json"},
+			RunOpts{StdinReader: strings.NewReader(nsYAML)})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		dryRunByKind := map[string]string{}
+		for _, row := range resp.Tables[0].Rows {
+			dryRunByKind[row["kind"]] = row["dry_run"]
+		}
+
+		require.Equal(t, map[string]string{"Namespace": "ok", "ConfigMap": ""}, dryRunByKind)
+
+		NewPresentClusterResource("configmap", "cm", "test-diff-server-side-dry-run-ns", kubectl)
+	})
+}
@@ -332,8 +332,8 @@ data:
 @@ create secret/with-keys (v1) namespace: kapp-test @@
       0 + apiVersion: v1
       1 + data:
-      2 +   key1: <-- value not shown (#1)
-      3 +   key2: <-- value not shown (#2)
+      2 +   key1: <-- value not shown (fingerprint: #1, added)
+      3 +   key2: <-- value not shown (fingerprint: #2, added)
       4 + kind: Secret
       5 + metadata:
       6 +   labels:
@@ -345,8 +345,8 @@ data:
 @@ create secret/with-dup-keys (v1) namespace: kapp-test @@
       0 + apiVersion: v1
       1 + data:
-      2 +   key1: <-- value not shown (#1)
-      3 +   key2: <-- value not shown (#2)
+      2 +   key1: <-- value not shown (fingerprint: #1, added)
+      3 +   key2: <-- value not shown (fingerprint: #2, added)
       4 + kind: Secret
       5 + metadata:
       6 +   labels:
@@ -357,19 +357,21 @@ data:
      11 + 
 `
 
-	out = replaceAnnsLabels(out)
+	out = replaceFingerprints(replaceAnnsLabels(out))
 
 	require.Containsf(t, out, expectedOutput, "Did not find expected diff output")
 
 	out, _ = kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "-c", "-p"},
 		RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
 
+	out = replaceFingerprints(out)
+
 	expectedOutput = `
 @@ update secret/with-dup-keys (v1) namespace: kapp-test @@
   ...
-  2,  2     key1: <-- value not shown (#1)
-  3     -   key2: <-- value not shown (#2)
-      3 +   key2: <-- value not shown (#3)
+  2,  2     key1: <-- value not shown (fingerprint: #1)
+  3     -   key2: <-- value not shown (fingerprint: #2, changed)
+      3 +   key2: <-- value not shown (fingerprint: #3, changed)
   4,  4   kind: Secret
   5,  5   metadata:
 `
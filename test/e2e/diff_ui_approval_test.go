@@ -0,0 +1,175 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffUIApproval(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm-approval
+data:
+  key: value1
+`
+
+	yaml2 := strings.ReplaceAll(yaml1, "value1", "value2")
+
+	name := "test-diff-ui-approval"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("approve changes", func() {
+		diffUI := startDiffUIDeploy(t, kapp, name, yaml1)
+
+		page := diffUI.Page()
+		require.Contains(t, page, `"name":"cm-approval"`)
+		require.Contains(t, page, `"groups":`)
+		require.NotContains(t, page, diffUI.token, "Expected approval token to not be included in page")
+
+		resp := diffUI.Post("/approve", "invalid-token")
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = diffUI.PostWithHost("/approve", diffUI.token, "attacker.example.com")
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = diffUI.Post("/approve", diffUI.token)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		require.NoError(t, diffUI.Wait())
+
+		cm := NewPresentClusterResource("configmap", "cm-approval", env.Namespace, kubectl)
+		require.Equal(t, "value1", cm.RawPath(ctlres.NewPathFromStrings([]string{"data", "key"})))
+	})
+
+	logger.Section("cancel changes", func() {
+		diffUI := startDiffUIDeploy(t, kapp, name, yaml2)
+
+		resp := diffUI.Post("/cancel", diffUI.token)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		err := diffUI.Wait()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Changes were cancelled in diff UI")
+
+		cm := NewPresentClusterResource("configmap", "cm-approval", env.Namespace, kubectl)
+		require.Equal(t, "value1", cm.RawPath(ctlres.NewPathFromStrings([]string{"data", "key"})))
+	})
+
+	logger.Section("skip approval when prompts are skipped", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-ui-alpha"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		require.NotContains(t, out, "Diff UI server")
+
+		cm := NewPresentClusterResource("configmap", "cm-approval", env.Namespace, kubectl)
+		require.Equal(t, "value2", cm.RawPath(ctlres.NewPathFromStrings([]string{"data", "key"})))
+	})
+}
+
+type diffUIDeploy struct {
+	t     *testing.T
+	url   string
+	token string
+	errCh chan error
+}
+
+func startDiffUIDeploy(t *testing.T, kapp Kapp, name, yaml string) diffUIDeploy {
+	outputReader, outputWriter, err := os.Pipe()
+	require.NoError(t, err)
+
+	errCh := make(chan error, 1)
+	urlCh := make(chan string, 1)
+
+	go func() {
+		// Approval via diff UI is only required when prompts are not skipped via --yes
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-ui-alpha"},
+			RunOpts{IntoNs: true, AllowError: true, Interactive: true,
+				StdinReader: strings.NewReader(yaml), StdoutWriter: outputWriter})
+		outputWriter.Close()
+		errCh <- err
+	}()
+
+	go func() {
+		scanner := bufio.NewScanner(outputReader)
+		for scanner.Scan() {
+			if strings.HasPrefix(scanner.Text(), "Diff UI server: ") {
+				urlCh <- strings.TrimPrefix(scanner.Text(), "Diff UI server: ")
+			}
+		}
+		// Keep draining output so that kapp does not block on writes
+		io.Copy(io.Discard, outputReader)
+	}()
+
+	select {
+	case url := <-urlCh:
+		// Approval token is only included in URL fragment
+		matches := diffUIApprovalURLRegexp.FindStringSubmatch(url)
+		require.Len(t, matches, 3, "Expected approval token to be included in URL")
+		return diffUIDeploy{t, matches[1], matches[2], errCh}
+	case err := <-errCh:
+		require.FailNowf(t, "Expected diff UI server to start", "Error: %v", err)
+		return diffUIDeploy{}
+	}
+}
+
+func (d diffUIDeploy) Page() string {
+	resp, err := http.Get(d.url)
+	require.NoError(d.t, err)
+
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	require.NoError(d.t, err)
+
+	page := string(bs)
+	require.Contains(d.t, page, "Approve")
+	return page
+}
+
+var diffUIApprovalURLRegexp = regexp.MustCompile(`^(http://[^/]+)/#approvalToken=([0-9a-f]+)$`)
+
+func (d diffUIDeploy) Post(path, token string) *http.Response {
+	return d.PostWithHost(path, token, "")
+}
+
+func (d diffUIDeploy) PostWithHost(path, token, host string) *http.Response {
+	req, err := http.NewRequest(http.MethodPost, d.url+path, nil)
+	require.NoError(d.t, err)
+
+	req.Header.Set("X-Kapp-Approval-Token", token)
+	if len(host) > 0 {
+		req.Host = host
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(d.t, err)
+
+	resp.Body.Close()
+	return resp
+}
+
+func (d diffUIDeploy) Wait() error { return <-d.errCh }
@@ -0,0 +1,87 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrift(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: drift-config
+data:
+  key: value1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: drift-svc
+spec:
+  ports:
+  - port: 80
+  selector:
+    app: drift
+`
+
+	name := "test-drift"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("no drift right after deploy", func() {
+		_, err := kapp.RunWithOpts([]string{"drift", "-a", name, "--exit-status"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "exit code: '2'")
+	})
+
+	logger.Section("reports fields changed outside of kapp", func() {
+		kubectl.Run([]string{"patch", "configmap", "drift-config", "--type", "merge", "-p", `{"data":{"key":"changed"}}`})
+
+		_, err := kapp.RunWithOpts([]string{"drift", "-a", name, "--exit-status"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "exit code: '3'")
+
+		out, _ := kapp.RunWithOpts([]string{"drift", "-a", name, "--json"}, RunOpts{})
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		rows := map[string]map[string]string{}
+		for _, row := range resp.Tables[0].Rows {
+			rows[row["name"]] = row
+		}
+
+		require.Equal(t, "drifted", rows["drift-config"]["drift"])
+		require.Equal(t, "/data/key", rows["drift-config"]["fields"])
+		require.Equal(t, "in sync", rows["drift-svc"]["drift"])
+	})
+
+	logger.Section("redeploy resolves drift", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		_, err := kapp.RunWithOpts([]string{"drift", "-a", name, "--exit-status"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "exit code: '2'")
+	})
+}
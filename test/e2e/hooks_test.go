@@ -0,0 +1,148 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHooks(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	job := `
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: %name%
+  annotations:
+    kapp.k14s.io/hook: %hook%
+    kapp.k14s.io/hook-delete-policy: %policy%
+spec:
+  template:
+    spec:
+      containers:
+      - name: hook
+        image: busybox
+        command: ["/bin/sh", "-c", "echo done"]
+      restartPolicy: Never
+`
+
+	newJob := func(name, hook, policy string) string {
+		return strings.NewReplacer("%name%", name, "%hook%", hook, "%policy%", policy).Replace(job)
+	}
+
+	yaml1 := newJob("pre-apply-hook", "pre-apply", "before-hook-creation") +
+		newJob("post-apply-hook", "post-apply", "hook-succeeded") +
+		newJob("pre-delete-hook", "pre-delete", "hook-succeeded") + `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: hooks-config
+`
+
+	name := "test-hooks"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	var preApplyHookUID string
+
+	logger.Section("deploy runs apply hooks", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		require.Contains(t, out, "create job/pre-apply-hook")
+		require.Less(t, strings.Index(out, "create job/pre-apply-hook"), strings.Index(out, "create configmap/hooks-config"))
+		require.Less(t, strings.Index(out, "create configmap/hooks-config"), strings.Index(out, "create job/post-apply-hook"))
+		require.NotContains(t, out, "pre-delete-hook")
+
+		preApplyHookUID = NewPresentClusterResource("job", "pre-apply-hook", env.Namespace, kubectl).UID()
+		NewPresentClusterResource("configmap", "hooks-config", env.Namespace, kubectl)
+		NewMissingClusterResource(t, "job", "post-apply-hook", env.Namespace, kubectl)
+		NewMissingClusterResource(t, "job", "pre-delete-hook", env.Namespace, kubectl)
+	})
+
+	logger.Section("redeploy recreates apply hooks", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		uid := NewPresentClusterResource("job", "pre-apply-hook", env.Namespace, kubectl).UID()
+		require.NotEqual(t, preApplyHookUID, uid)
+		NewMissingClusterResource(t, "job", "post-apply-hook", env.Namespace, kubectl)
+	})
+
+	logger.Section("delete runs delete hooks", func() {
+		out, _ := kapp.RunWithOpts([]string{"delete", "-a", name}, RunOpts{})
+
+		require.Contains(t, out, "create job/pre-delete-hook")
+		require.Less(t, strings.Index(out, "create job/pre-delete-hook"), strings.Index(out, "delete configmap/hooks-config"))
+		NewMissingClusterResource(t, "job", "pre-delete-hook", env.Namespace, kubectl)
+		NewMissingClusterResource(t, "configmap", "hooks-config", env.Namespace, kubectl)
+	})
+
+	logger.Section("delete runs and deletes delete hooks with default delete policy", func() {
+		yaml2 := newJob("pre-delete-default-hook", "pre-delete", "before-hook-creation") +
+			newJob("post-delete-hook", "post-delete", "before-hook-creation") + `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: hooks-config
+`
+
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		NewMissingClusterResource(t, "job", "pre-delete-default-hook", env.Namespace, kubectl)
+		NewMissingClusterResource(t, "job", "post-delete-hook", env.Namespace, kubectl)
+
+		out, _ := kapp.RunWithOpts([]string{"delete", "-a", name}, RunOpts{})
+
+		require.Less(t, strings.Index(out, "create job/pre-delete-default-hook"), strings.Index(out, "delete configmap/hooks-config"))
+		require.Less(t, strings.Index(out, "delete configmap/hooks-config"), strings.Index(out, "create job/post-delete-hook"))
+
+		// Hooks are not left behind without an owning app
+		NewMissingClusterResource(t, "job", "pre-delete-default-hook", env.Namespace, kubectl)
+		NewMissingClusterResource(t, "job", "post-delete-hook", env.Namespace, kubectl)
+		NewMissingClusterResource(t, "configmap", "hooks-config", env.Namespace, kubectl)
+	})
+
+	logger.Section("partial delete does not run delete hooks", func() {
+		yaml3 := newJob("pre-delete-hook", "pre-delete", "before-hook-creation") + `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: hooks-config
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: hooks-config-2
+`
+
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml3)})
+
+		out, _ := kapp.RunWithOpts([]string{"delete", "-a", name,
+			"--diff-filter", `{"existingResource": {"names": ["hooks-config"]}}`}, RunOpts{})
+
+		require.Contains(t, out, "will not be fully deleted")
+		require.NotContains(t, out, "pre-delete-hook")
+		NewMissingClusterResource(t, "job", "pre-delete-hook", env.Namespace, kubectl)
+		NewMissingClusterResource(t, "configmap", "hooks-config", env.Namespace, kubectl)
+		NewPresentClusterResource("configmap", "hooks-config-2", env.Namespace, kubectl)
+	})
+}
@@ -0,0 +1,227 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportHelm(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	manifest := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: helm-config1
+  labels:
+    app.kubernetes.io/managed-by: Helm
+data:
+  key: value1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: helm-config2
+  labels:
+    app.kubernetes.io/managed-by: Helm
+data:
+  key: value2
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: helm-deployment
+  labels:
+    app.kubernetes.io/managed-by: Helm
+  annotations:
+    kapp.k14s.io/disable-default-ownership-label-rules: ""
+    kapp.k14s.io/disable-default-label-scoping-rules: ""
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: helm-deployment
+  template:
+    metadata:
+      labels:
+        app: helm-deployment
+    spec:
+      containers:
+      - name: busybox
+        image: busybox
+        command: ["sleep", "3600"]
+`
+
+	unscopedManifest := `
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: helm-unscoped-deployment
+spec:
+  selector:
+    matchLabels:
+      app: helm-unscoped-deployment
+  template:
+    metadata:
+      labels:
+        app: helm-unscoped-deployment
+    spec:
+      containers:
+      - name: busybox
+        image: busybox
+        command: ["sleep", "3600"]
+`
+
+	releaseName := "test-import-helm-release"
+	unscopedReleaseName := "test-import-helm-unscoped-release"
+	name := "test-import-helm"
+
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+		for _, relName := range []string{releaseName, unscopedReleaseName} {
+			kubectl.RunWithOpts([]string{"delete", "secret,configmap", "-l", "owner=helm,name=" + relName}, RunOpts{AllowError: true})
+		}
+		kubectl.RunWithOpts([]string{"delete", "configmap", "helm-config1", "helm-config2"}, RunOpts{AllowError: true})
+		kubectl.RunWithOpts([]string{"delete", "deployment", "helm-deployment", "helm-unscoped-deployment"}, RunOpts{AllowError: true})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("simulate Helm release installation", func() {
+		kubectl.RunWithOpts([]string{"create", "-f", "-"}, RunOpts{StdinReader: strings.NewReader(manifest)})
+		kubectl.RunWithOpts([]string{"create", "-f", "-"},
+			RunOpts{StdinReader: strings.NewReader(helmReleaseSecret(t, releaseName, env.Namespace, manifest))})
+		// Helm annotates live resources with release they belong to
+		kubectl.Run([]string{"annotate", "configmap/helm-config1", "configmap/helm-config2", "deployment/helm-deployment",
+			"meta.helm.sh/release-name=" + releaseName, "meta.helm.sh/release-namespace=" + env.Namespace})
+	})
+
+	logger.Section("simulate drift after Helm release installation", func() {
+		kubectl.Run([]string{"scale", "deployment/helm-deployment", "--replicas", "2"})
+	})
+
+	logger.Section("import release with resources changed by label rules fails", func() {
+		kubectl.RunWithOpts([]string{"create", "-f", "-"}, RunOpts{StdinReader: strings.NewReader(unscopedManifest)})
+		kubectl.RunWithOpts([]string{"create", "-f", "-"},
+			RunOpts{StdinReader: strings.NewReader(helmReleaseSecret(t, unscopedReleaseName, env.Namespace, unscopedManifest))})
+
+		_, err := kapp.RunWithOpts([]string{"app", "import-helm", "--release", unscopedReleaseName, "-a", name},
+			RunOpts{IntoNs: true, AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected Helm release resources to not be changed by label rules on subsequent deploys, "+
+			"but these would be: deployment/helm-unscoped-deployment")
+
+		labels := NewPresentClusterResource("deployment", "helm-unscoped-deployment", env.Namespace, kubectl).Labels()
+		require.NotContains(t, labels, "kapp.k14s.io/app")
+	})
+
+	logger.Section("import release", func() {
+		out, _ := kapp.RunWithOpts([]string{"app", "import-helm", "--release", releaseName, "-a", name},
+			RunOpts{IntoNs: true})
+
+		require.Contains(t, out, fmt.Sprintf("Importing Helm release '%s' (revision 2, 3 resources)", releaseName))
+
+		for _, cmName := range []string{"helm-config1", "helm-config2"} {
+			cm := NewPresentClusterResource("configmap", cmName, env.Namespace, kubectl)
+			require.Contains(t, cm.Labels(), "kapp.k14s.io/app")
+			require.NotContains(t, cm.RawPath(ctlres.NewPathFromStrings([]string{"metadata", "annotations"})),
+				"meta.helm.sh/release-name")
+		}
+
+		dep := NewPresentClusterResource("deployment", "helm-deployment", env.Namespace, kubectl)
+		require.Contains(t, dep.Labels(), "kapp.k14s.io/app")
+
+		// Only metadata is updated, hence selector, pod template and drift are left as is
+		require.Equal(t, map[string]interface{}{"app": "helm-deployment"},
+			dep.RawPath(ctlres.NewPathFromStrings([]string{"spec", "selector", "matchLabels"})))
+		require.Equal(t, map[string]interface{}{"app": "helm-deployment"},
+			dep.RawPath(ctlres.NewPathFromStrings([]string{"spec", "template", "metadata", "labels"})))
+		require.EqualValues(t, 2, dep.RawPath(ctlres.NewPathFromStrings([]string{"spec", "replicas"})))
+	})
+
+	logger.Section("deploy after import has no changes", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--json"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(manifest)})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		require.Len(t, resp.Tables[0].Rows, 0, "Expected to see no changes, but did not")
+
+		dep := NewPresentClusterResource("deployment", "helm-deployment", env.Namespace, kubectl)
+		require.EqualValues(t, 2, dep.RawPath(ctlres.NewPathFromStrings([]string{"spec", "replicas"})))
+	})
+
+	logger.Section("import into existing app fails", func() {
+		_, err := kapp.RunWithOpts([]string{"app", "import-helm", "--release", releaseName, "-a", name},
+			RunOpts{IntoNs: true, AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Helm releases can only be imported into new apps")
+	})
+}
+
+func helmReleaseSecret(t *testing.T, releaseName, ns, manifest string) string {
+	var secrets []string
+
+	// Older revision is superseded hence should not be imported
+	for _, rev := range []struct {
+		Version  int
+		Status   string
+		Manifest string
+	}{{1, "superseded", ""}, {2, "deployed", manifest}} {
+		releaseJSON, err := json.Marshal(map[string]interface{}{
+			"name":      releaseName,
+			"namespace": ns,
+			"version":   rev.Version,
+			"manifest":  rev.Manifest,
+			"info":      map[string]interface{}{"status": rev.Status},
+		})
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		_, err = writer.Write(releaseJSON)
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		// Helm encodes release itself and then it is encoded again as secret data
+		encodedRelease := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+		secrets = append(secrets, fmt.Sprintf(`
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: sh.helm.release.v1.%[1]s.v%[2]d
+  labels:
+    owner: helm
+    name: %[1]s
+    status: %[3]s
+    version: "%[2]d"
+type: helm.sh/release.v1
+data:
+  release: %[4]s
+`, releaseName, rev.Version, rev.Status, base64.StdEncoding.EncodeToString([]byte(encodedRelease))))
+	}
+
+	return strings.Join(secrets, "")
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiClusterDeploy(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  key: value
+`
+
+	name := "test-multi-cluster-deploy"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	out, _ := kubectl.RunWithOpts([]string{"config", "current-context"}, RunOpts{NoNamespace: true})
+	currentContext := strings.TrimSpace(out)
+
+	logger.Section("diff against multiple contexts", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "-c",
+			"--kubeconfig-context", currentContext + "," + currentContext},
+			RunOpts{StdinReader: strings.NewReader(yaml1)})
+
+		require.Equal(t, 2, strings.Count(out, currentContext+" | "+"@@ create configmap/cm1"),
+			"Expected changes to be shown for each context")
+
+		NewMissingClusterResource(t, "configmap", "cm1", env.Namespace, kubectl)
+	})
+
+	logger.Section("invalid targets file", func() {
+		targetsFile := writeTargetsFile(t, `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Unknown
+targets:
+- context: `+currentContext)
+		defer os.Remove(targetsFile)
+
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--targets-file", targetsFile},
+			RunOpts{AllowError: true, StdinReader: strings.NewReader(yaml1)})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Validating kind: Unknown kind (known: DeployTargets)")
+	})
+
+	logger.Section("deploy stops after cluster fails", func() {
+		// Same cluster is listed multiple times hence planned changes
+		// for second target no longer match cluster once first target is applied
+		targetsFile := writeTargetsFile(t, fmt.Sprintf(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: DeployTargets
+targets:
+- context: %[1]s
+- context: %[1]s
+- context: %[1]s
+`, currentContext))
+		defer os.Remove(targetsFile)
+
+		out, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--targets-file", targetsFile},
+			RunOpts{AllowError: true, StdinReader: strings.NewReader(yaml1)})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Refusing to apply deploy plan since calculated changes differ from planned changes")
+
+		require.Contains(t, out, "Changes will be applied to 3 clusters (in order)")
+		require.Contains(t, out, "succeeded")
+		require.Contains(t, out, "failed")
+		require.Contains(t, out, "skipped (previous cluster failed)")
+
+		NewPresentClusterResource("configmap", "cm1", env.Namespace, kubectl)
+	})
+
+	logger.Section("deploy with no changes", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name,
+			"--kubeconfig-context", currentContext + "," + currentContext},
+			RunOpts{StdinReader: strings.NewReader(yaml1)})
+
+		require.Equal(t, 2, strings.Count(out, "succeeded"), "Expected both clusters to succeed")
+	})
+}
+
+func writeTargetsFile(t *testing.T, content string) string {
+	file, err := os.CreateTemp("", "kapp-test-targets")
+	require.NoError(t, err)
+
+	defer file.Close()
+
+	_, err = file.Write([]byte(content))
+	require.NoError(t, err)
+
+	return file.Name()
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRisk(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	config := `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+riskRules:
+- resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: ConfigMap}
+  ops: [update]
+  paths:
+  - [data, mode]
+  level: high
+  reason: changes mode
+`
+
+	svc := `
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  selector:
+    app: svc
+  ports:
+  - name: http
+    port: 80
+`
+
+	cm := func(mode, other string) string {
+		return `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  mode: ` + mode + `
+  other: ` + other + `
+`
+	}
+
+	name := "test-risk"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(config + svc + cm("a", "a"))})
+	})
+
+	logger.Section("risk column is hidden when there are no risky changes", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--json", "--diff-run"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(config + svc + cm("a", "b"))})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		for _, row := range resp.Tables[0].Rows {
+			require.NotContains(t, row, "risk")
+		}
+		for _, note := range resp.Tables[0].Notes {
+			require.NotContains(t, note, "Risk:")
+		}
+	})
+
+	logger.Section("risky changes are classified in changes summary", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--json", "--diff-run"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(config + cm("b", "a"))})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		risks := map[string]string{}
+		for _, row := range resp.Tables[0].Rows {
+			risks[row["kind"]+"/"+row["name"]] = row["risk"]
+		}
+
+		require.Equal(t, "high: changes mode", risks["ConfigMap/cm"])
+		require.Equal(t, "medium: deletes service (clients may lose connectivity)", risks["Service/svc"])
+		require.Equal(t, "Risk:    1 high, 1 medium, 0 low", resp.Tables[0].Notes[2])
+	})
+
+	logger.Section("deploy fails on risky changes at or above given level", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--fail-on-risk", "medium"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(config + cm("b", "a"))})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Refusing to apply 2 change(s) with risk level 'medium' or higher:\n"+
+			"- configmap/cm (v1) namespace: "+env.Namespace+": high: changes mode\n"+
+			"- service/svc (v1) namespace: "+env.Namespace+": medium: deletes service (clients may lose connectivity)")
+	})
+
+	logger.Section("deploy fails on invalid risk level", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--fail-on-risk", "severe"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(config + svc + cm("a", "a"))})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected risk level to be one of: low, medium, high")
+	})
+
+	logger.Section("deploy succeeds when risky changes are below given level", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--fail-on-risk", "high"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(config + cm("a", "b"))})
+	})
+}
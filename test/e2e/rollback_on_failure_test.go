@@ -0,0 +1,108 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollbackOnFailure(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: rollback-config
+data:
+  key: value1
+`
+
+	yaml2 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: rollback-config
+  annotations:
+    kapp.k14s.io/change-group: config
+data:
+  key: value2
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: rollback-invalid
+  annotations:
+    kapp.k14s.io/change-rule: upsert after upserting config
+spec:
+  ports:
+  - port: 99999
+  selector:
+    app: rollback
+`
+
+	protectedYAML := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: rollback-protected
+  annotations:
+    kapp.k14s.io/change-group: config
+    kapp.k14s.io/prevent-delete: ""
+`
+
+	name := "test-rollback-on-failure"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name,
+			"--dangerous-allow-deleting-protected-resource", "ConfigMap/" + env.Namespace + "/rollback-protected"})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy successfully", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("deploy failure rolls back to previous app change", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--rollback-on-failure"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml2)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "rolled back to app change")
+
+		cm := NewPresentClusterResource("configmap", "rollback-config", env.Namespace, kubectl)
+		require.Equal(t, "value1", cm.RawPath(ctlres.NewPathFromStrings([]string{"data", "key"})))
+
+		out, _ := kapp.RunWithOpts([]string{"app-change", "ls", "-a", name, "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Equal(t, 2, len(resp.Tables[0].Rows), "Expected to have 2 app-changes")
+		require.Equal(t, "false", resp.Tables[0].Rows[0]["successful"])
+		require.Contains(t, resp.Tables[0].Rows[0]["failure_reason"], "rolled back to app change")
+	})
+
+	logger.Section("rollback does not delete protected resources", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--rollback-on-failure"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml2 + protectedYAML)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "rollback failed: Refusing to delete 1 protected resource(s): "+
+			"ConfigMap/"+env.Namespace+"/rollback-protected")
+
+		NewPresentClusterResource("configmap", "rollback-protected", env.Namespace, kubectl)
+	})
+}
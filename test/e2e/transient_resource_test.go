@@ -97,6 +97,7 @@ spec:
 			"namespace":       "kapp-test",
 			"reconcile_info":  "",
 			"reconcile_state": "ok",
+			"risk":            "",
 		}, {
 			"age":             "<replaced>",
 			"op":              "delete",
@@ -107,6 +108,7 @@ spec:
 			"namespace":       "kapp-test",
 			"reconcile_info":  "",
 			"reconcile_state": "ok",
+			"risk":            "medium: deletes service (clients may lose connectivity)",
 		}}
 
 		if hasEndpointSlice(respRows) {
@@ -121,6 +123,7 @@ spec:
 				"namespace":       "kapp-test",
 				"reconcile_info":  "",
 				"reconcile_state": "ok",
+				"risk":            "",
 			})
 		}
 
@@ -205,6 +208,7 @@ metadata:
 			"namespace":       "kapp-test",
 			"reconcile_info":  "",
 			"reconcile_state": "ok",
+			"risk":            "",
 		}, {
 			"age":             "<replaced>",
 			"op":              "delete",
@@ -215,6 +219,7 @@ metadata:
 			"namespace":       "kapp-test",
 			"reconcile_info":  "",
 			"reconcile_state": "ok",
+			"risk":            "medium: deletes service (clients may lose connectivity)",
 		}}
 
 		if hasEndpointSlice(respRows) {
@@ -229,6 +234,7 @@ metadata:
 				"namespace":       "kapp-test",
 				"reconcile_info":  "",
 				"reconcile_state": "ok",
+				"risk":            "",
 			})
 		}
 
@@ -0,0 +1,79 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateServerSideApply(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ssa-config
+  annotations:
+    kapp.k14s.io/update-strategy: server-side-apply
+data:
+  key1: value1
+`
+
+	yaml2 := strings.Replace(yaml1, "key1: value1", "key1: value1-updated", 1)
+
+	yaml3 := strings.Replace(yaml1, "key1: value1", "key1: value1-updated\n  key2: value2-kapp", 1)
+
+	name := "test-update-server-side-apply"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy initial", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("add field by another field manager", func() {
+		kubectl.RunWithOpts([]string{"patch", "configmap", "ssa-config", "--type=merge",
+			"--field-manager", "other-controller", "--patch", `{"data":{"key2":"value2"}}`}, RunOpts{})
+	})
+
+	logger.Section("deploy update keeps fields owned by another field manager", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		cm := NewPresentClusterResource("configmap", "ssa-config", env.Namespace, kubectl)
+		require.Equal(t, "value1-updated", cm.RawPath(ctlres.NewPathFromStrings([]string{"data", "key1"})))
+		require.Equal(t, "value2", cm.RawPath(ctlres.NewPathFromStrings([]string{"data", "key2"})))
+	})
+
+	logger.Section("deploy update that conflicts with another field manager", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml3)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Server-side apply conflicts with other field managers")
+		require.Contains(t, err.Error(), "other-controller")
+	})
+
+	logger.Section("deploy update that forces conflicts", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--apply-server-side-force-conflicts"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml3)})
+
+		cm := NewPresentClusterResource("configmap", "ssa-config", env.Namespace, kubectl)
+		require.Equal(t, "value2-kapp", cm.RawPath(ctlres.NewPathFromStrings([]string{"data", "key2"})))
+	})
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	ctlres "carvel.dev/kapp/pkg/kapp/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateThreeWayMergePatch(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: merge-patch-config
+  annotations:
+    kapp.k14s.io/update-strategy: three-way-merge-patch
+data:
+  key1: value1
+  key3: value3
+`
+
+	yaml2 := strings.Replace(yaml1, "key1: value1\n  key3: value3", "key1: value1-updated", 1)
+
+	name := "test-update-three-way-merge-patch"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy initial", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("add field by another controller", func() {
+		kubectl.RunWithOpts([]string{"patch", "configmap", "merge-patch-config", "--type=merge",
+			"--patch", `{"data":{"key2":"value2"}}`}, RunOpts{})
+	})
+
+	logger.Section("deploy update keeps fields set by another controller", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		cm := NewPresentClusterResource("configmap", "merge-patch-config", env.Namespace, kubectl)
+		require.Equal(t, map[string]interface{}{
+			"key1": "value1-updated",
+			"key2": "value2",
+		}, cm.RawPath(ctlres.NewPathFromStrings([]string{"data"})))
+	})
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 The Carvel Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitWatch(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: wait-watch-dep
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: wait-watch-dep
+  template:
+    metadata:
+      labels:
+        app: wait-watch-dep
+    spec:
+      containers:
+      - name: sleep
+        image: busybox
+        command: ["sleep", "3600"]
+`
+
+	name := "test-wait-watch"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy finishes waiting once resources change instead of after check interval", func() {
+		startTime := time.Now()
+
+		// Check interval is larger than wait timeout, so deploy
+		// can only succeed if resource changes are watched
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name,
+			"--wait-check-interval", "1h", "--wait-timeout", "3m"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		require.Less(t, time.Since(startTime), 3*time.Minute)
+	})
+
+	logger.Section("delete finishes waiting once resources are gone", func() {
+		out, _ := kapp.RunWithOpts([]string{"delete", "-a", name,
+			"--wait-check-interval", "1h", "--wait-timeout", "3m"}, RunOpts{})
+
+		require.Contains(t, out, "Succeeded")
+	})
+}